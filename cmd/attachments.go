@@ -3,14 +3,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/progress"
 	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
+var (
+	downloadThreadFlag       int
+	downloadConversationFlag int
+	downloadDirFlag          string
+	downloadConcurrencyFlag  int
+	uploadNameFlag           string
+	uploadCommentFlag        string
+	attachFlag               []string
+)
+
 var attachmentsCmd = &cobra.Command{
 	Use:   "attachments",
 	Short: "Manage attachments",
@@ -18,47 +33,175 @@ var attachmentsCmd = &cobra.Command{
 }
 
 var attachmentsUploadCmd = &cobra.Command{
-	Use:   "upload [target-type] [target-id] [file-path]",
-	Short: "Upload a file attachment",
-	Long:  `Upload a file to a thread, comment, or conversation. Target type must be 'thread', 'comment', or 'conversation'.`,
-	Args:  cobra.ExactArgs(3),
+	Use:   "upload [target-type] [target-id] [file-path...]",
+	Short: "Upload file attachments",
+	Long: `Upload one or more files to a thread, comment, or conversation. Target type
+must be 'thread', 'comment', or 'conversation'.
+
+File paths may be glob patterns such as '*.png'. Use '-' to read a file from
+stdin, naming it with --name. With --comment, a comment (or, for a
+conversation, a message) linking to the uploaded files is posted afterwards.`,
+	Args: cobra.MinimumNArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		targetType := args[0]
 		targetID, err := strconv.Atoi(args[1])
 		if err != nil {
 			return fmt.Errorf("invalid target ID: %w", err)
 		}
-		filePath := args[2]
 
 		if targetType != "thread" && targetType != "comment" && targetType != "conversation" {
 			return fmt.Errorf("invalid target type: must be 'thread', 'comment', or 'conversation'")
 		}
+		if uploadCommentFlag != "" && targetType == "comment" {
+			return fmt.Errorf("--comment needs a thread or conversation target")
+		}
+
+		paths, err := expandUploadPaths(args[2:])
+		if err != nil {
+			return err
+		}
+		if slices.Contains(paths, "-") && uploadNameFlag == "" {
+			return fmt.Errorf("--name is required when uploading from stdin")
+		}
 
 		token, err := auth.GetToken(tokenFlag)
 		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
-		attachment, err := client.UploadAttachment(targetType, targetID, filePath)
-		if err != nil {
-			return fmt.Errorf("failed to upload attachment: %w", err)
+		client := newClient(token)
+		var uploaded []*api.Attachment
+		for _, path := range paths {
+			attachment, err := uploadFile(client, targetType, targetID, path)
+			if err != nil {
+				return fmt.Errorf("failed to upload %s: %w", path, err)
+			}
+			uploaded = append(uploaded, attachment)
+			fmt.Printf("✓ %s (ID %d, %s, %s)\n", attachment.Title, attachment.ID,
+				progress.FormatBytes(attachment.Size), attachment.MimeType)
 		}
 
-		fmt.Printf("Attachment uploaded successfully!\n")
-		fmt.Printf("Attachment ID: %d\n", attachment.ID)
-		fmt.Printf("Title: %s\n", attachment.Title)
-		fmt.Printf("Size: %d bytes\n", attachment.Size)
+		if uploadCommentFlag != "" {
+			content := uploadCommentFlag + "\n"
+			for _, a := range uploaded {
+				content += fmt.Sprintf("\n- [%s](%s)", a.Title, a.URL)
+			}
+			if targetType == "thread" {
+				comment, err := client.PostComment(targetID, content, nil)
+				if err != nil {
+					return fmt.Errorf("failed to post comment: %w", err)
+				}
+				fmt.Printf("Comment posted (ID %d)\n", comment.ID)
+			} else {
+				message, err := client.SendConversationMessage(targetID, content, nil)
+				if err != nil {
+					return fmt.Errorf("failed to send message: %w", err)
+				}
+				fmt.Printf("Message sent (ID %d)\n", message.ID)
+			}
+		}
 
+		if len(uploaded) > 1 {
+			fmt.Printf("\nUploaded %d files\n", len(uploaded))
+		}
 		return nil
 	},
 }
 
+// expandUploadPaths expands glob patterns among the upload arguments,
+// keeping plain paths and "-" as they are.
+func expandUploadPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if arg == "-" || !strings.ContainsAny(arg, "*?[") {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// checkAttachPaths expands the --attach arguments and makes sure each is a
+// readable file, so that nothing is posted when one of them is missing.
+func checkAttachPaths(args []string) ([]string, error) {
+	paths, err := expandUploadPaths(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if path == "-" {
+			return nil, fmt.Errorf("--attach cannot read from stdin")
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --attach: %w", err)
+		}
+		if fi.IsDir() {
+			return nil, fmt.Errorf("invalid --attach: %s is a directory", path)
+		}
+	}
+	return paths, nil
+}
+
+// uploadAttachments uploads files to be included in a new post. In a dry run
+// each upload is printed and stands in as an attachment with only a title.
+func uploadAttachments(client *api.Client, paths []string) ([]api.Attachment, error) {
+	var attachments []api.Attachment
+	for _, path := range paths {
+		attachment, err := uploadFile(client, "", 0, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %w", path, err)
+		}
+		if dryRunFlag {
+			attachment.Title = filepath.Base(path)
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, nil
+}
+
+// uploadFile uploads a file, or stdin for "-", showing a progress bar.
+func uploadFile(client *api.Client, targetType string, targetID int, path string) (*api.Attachment, error) {
+	if path == "-" {
+		bar := progress.New(os.Stderr, uploadNameFlag, 0)
+		defer bar.Finish()
+		return client.UploadAttachmentFrom(targetType, targetID, uploadNameFlag, os.Stdin, bar.Add)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	name := filepath.Base(path)
+	bar := progress.New(os.Stderr, name, fi.Size())
+	defer bar.Finish()
+	return client.UploadAttachmentFrom(targetType, targetID, name, file, bar.Add)
+}
+
 var attachmentsDownloadCmd = &cobra.Command{
 	Use:   "download [attachment-id] [output-path]",
 	Short: "Download an attachment",
-	Long:  `Download an attachment by ID to a local file.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Download an attachment by ID to a local file. An interrupted download is
+resumed when the command is run again with the same output path.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		attachmentID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -71,8 +214,16 @@ var attachmentsDownloadCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
-		if err := client.DownloadAttachment(attachmentID, outputPath); err != nil {
+		client := newClient(token)
+		attachment, err := client.GetAttachment(attachmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get attachment: %w", err)
+		}
+
+		bar := progress.New(os.Stderr, attachment.Title, attachment.Size)
+		err = client.DownloadAttachmentFile(attachment, outputPath, bar.Add)
+		bar.Finish()
+		if err != nil {
 			return fmt.Errorf("failed to download attachment: %w", err)
 		}
 
@@ -81,6 +232,161 @@ var attachmentsDownloadCmd = &cobra.Command{
 	},
 }
 
+var attachmentsDownloadAllCmd = &cobra.Command{
+	Use:   "download-all",
+	Short: "Download every attachment of a thread or conversation",
+	Long: `Download the attachments of a thread (including its comments) or of a
+conversation into a directory, several at a time. Files that are already
+complete are skipped and interrupted ones are resumed, so the command can
+simply be run again after a failure.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (downloadThreadFlag == 0) == (downloadConversationFlag == 0) {
+			return fmt.Errorf("give exactly one of --thread or --conversation")
+		}
+		if downloadConcurrencyFlag < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		var attachments []api.Attachment
+		if downloadThreadFlag != 0 {
+			attachments, err = client.GetAttachments("thread", downloadThreadFlag)
+			if err != nil {
+				return fmt.Errorf("failed to get attachments: %w", err)
+			}
+			comments, err := client.GetComments(downloadThreadFlag)
+			if err != nil {
+				return fmt.Errorf("failed to get comments: %w", err)
+			}
+			for _, c := range comments {
+				more, err := client.GetAttachments("comment", c.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get attachments of comment %d: %w", c.ID, err)
+				}
+				attachments = append(attachments, more...)
+			}
+		} else {
+			attachments, err = client.GetAttachments("conversation", downloadConversationFlag)
+			if err != nil {
+				return fmt.Errorf("failed to get attachments: %w", err)
+			}
+		}
+
+		attachments = uniqueAttachments(attachments)
+		if len(attachments) == 0 {
+			fmt.Println("No attachments found.")
+			return nil
+		}
+
+		if err := os.MkdirAll(downloadDirFlag, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		paths := attachmentPaths(downloadDirFlag, attachments)
+		var pending []api.Attachment
+		var total int64
+		skipped := 0
+		for _, a := range attachments {
+			if fi, err := os.Stat(paths[a.ID]); err == nil && a.Size > 0 && fi.Size() == a.Size {
+				skipped++
+				continue
+			}
+			pending = append(pending, a)
+			total += a.Size
+		}
+
+		bar := progress.New(os.Stderr, fmt.Sprintf("Downloading %d files", len(pending)), total)
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			failures = make(map[int]error)
+			sem      = make(chan struct{}, downloadConcurrencyFlag)
+		)
+		for _, a := range pending {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(a api.Attachment) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := client.DownloadAttachmentFile(&a, paths[a.ID], bar.Add); err != nil {
+					mu.Lock()
+					failures[a.ID] = err
+					mu.Unlock()
+				}
+			}(a)
+		}
+		wg.Wait()
+		bar.Finish()
+
+		for _, a := range pending {
+			if err, ok := failures[a.ID]; ok {
+				fmt.Printf("✗ %s: %v\n", a.Title, err)
+			} else {
+				fmt.Printf("✓ %s\n", paths[a.ID])
+			}
+		}
+		if skipped > 0 {
+			fmt.Printf("Skipped %d files already downloaded\n", skipped)
+		}
+		fmt.Printf("\nDownloaded %d of %d attachments to %s\n", len(pending)-len(failures), len(pending), downloadDirFlag)
+		if len(failures) > 0 {
+			return fmt.Errorf("%d downloads failed; run the command again to resume", len(failures))
+		}
+		return nil
+	},
+}
+
+// uniqueAttachments drops repeated attachments, which the API can list
+// under both a thread and one of its comments.
+func uniqueAttachments(attachments []api.Attachment) []api.Attachment {
+	seen := make(map[int]bool, len(attachments))
+	var unique []api.Attachment
+	for _, a := range attachments {
+		if !seen[a.ID] {
+			seen[a.ID] = true
+			unique = append(unique, a)
+		}
+	}
+	return unique
+}
+
+// attachmentPaths picks a distinct file name in dir for each attachment,
+// prefixing the ID when several attachments share a title or a name is
+// already taken.
+func attachmentPaths(dir string, attachments []api.Attachment) map[int]string {
+	titles := make(map[string]int)
+	for _, a := range attachments {
+		titles[attachmentFileName(a)]++
+	}
+	paths := make(map[int]string)
+	used := make(map[string]bool)
+	for _, a := range attachments {
+		name := attachmentFileName(a)
+		if titles[name] > 1 {
+			name = fmt.Sprintf("%d-%s", a.ID, name)
+		}
+		for used[strings.ToLower(name)] {
+			name = fmt.Sprintf("%d-%s", a.ID, name)
+		}
+		used[strings.ToLower(name)] = true
+		paths[a.ID] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+func attachmentFileName(a api.Attachment) string {
+	name := filepath.Base(a.Title)
+	if name == "." || name == "/" || name == ".." {
+		name = fmt.Sprintf("attachment-%d", a.ID)
+	}
+	return name
+}
+
 var attachmentsListCmd = &cobra.Command{
 	Use:   "list [target-type] [target-id]",
 	Short: "List all attachments",
@@ -102,7 +408,7 @@ var attachmentsListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		attachments, err := client.GetAttachments(targetType, targetID)
 		if err != nil {
 			return fmt.Errorf("failed to get attachments: %w", err)
@@ -132,7 +438,15 @@ var attachmentsListCmd = &cobra.Command{
 }
 
 func init() {
+	attachmentsUploadCmd.Flags().StringVar(&uploadNameFlag, "name", "", "File name for data read from stdin")
+	attachmentsUploadCmd.Flags().StringVar(&uploadCommentFlag, "comment", "", "Post this text with links to the uploaded files")
+	attachmentsDownloadAllCmd.Flags().IntVar(&downloadThreadFlag, "thread", 0, "Thread whose attachments to download")
+	attachmentsDownloadAllCmd.Flags().IntVar(&downloadConversationFlag, "conversation", 0, "Conversation whose attachments to download")
+	attachmentsDownloadAllCmd.Flags().StringVar(&downloadDirFlag, "dir", ".", "Directory to download into")
+	attachmentsDownloadAllCmd.Flags().IntVar(&downloadConcurrencyFlag, "concurrency", 4, "Number of files to download at once")
+
 	attachmentsCmd.AddCommand(attachmentsUploadCmd)
 	attachmentsCmd.AddCommand(attachmentsDownloadCmd)
+	attachmentsCmd.AddCommand(attachmentsDownloadAllCmd)
 	attachmentsCmd.AddCommand(attachmentsListCmd)
 }
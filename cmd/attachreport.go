@@ -0,0 +1,492 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/progress"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormatFlag      string
+	reportTopFlag         int
+	reportConcurrencyFlag int
+)
+
+// attachmentRecord is an attachment together with where it was found.
+type attachmentRecord struct {
+	api.Attachment
+	ChannelID      int
+	ThreadID       int
+	CommentID      int
+	ConversationID int
+	Uploader       int
+	Checksum       string
+	DuplicateOf    int
+}
+
+var attachmentsReportCmd = &cobra.Command{
+	Use:   "report [workspace-id]",
+	Short: "Report attachment storage use in a workspace",
+	Long: `Crawl every channel, thread, comment and conversation of a workspace and
+report the attachments found, with total size by channel, uploader, file type
+and age, plus files uploaded more than once.
+
+Attachments of equal size are downloaded and compared by SHA-256 checksum to
+find duplicates. Use --format csv for one row per attachment instead of the
+summary tables.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid workspace ID: %w", err)
+		}
+		if reportFormatFlag != "table" && reportFormatFlag != "csv" {
+			return fmt.Errorf("invalid --format %q: must be table or csv", reportFormatFlag)
+		}
+		if reportConcurrencyFlag < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		dir, err := loadDirectory(client, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		records, err := crawlAttachments(client, dir, workspaceID, reportConcurrencyFlag)
+		if err != nil {
+			return err
+		}
+		if err := findDuplicates(client, records, reportConcurrencyFlag); err != nil {
+			return err
+		}
+
+		if reportFormatFlag == "csv" {
+			return writeAttachmentCSV(os.Stdout, dir, records)
+		}
+		printAttachmentReport(dir, records, reportTopFlag)
+		return nil
+	},
+}
+
+// crawlAttachments collects the attachments of every thread, comment and
+// conversation in a workspace. An attachment found on a comment is credited
+// to the comment rather than to its thread.
+func crawlAttachments(client *api.Client, dir *directory, workspaceID, concurrency int) ([]*attachmentRecord, error) {
+	var threads []api.Thread
+	for _, ch := range dir.channels {
+		if ch.WorkspaceID != workspaceID {
+			continue
+		}
+		chThreads, err := client.GetThreads(ch.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get threads of channel %d: %w", ch.ID, err)
+		}
+		threads = append(threads, chThreads...)
+	}
+	fmt.Fprintf(os.Stderr, "Scanning %d threads...\n", len(threads))
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		seen     = make(map[int]bool)
+		records  []*attachmentRecord
+		sem      = make(chan struct{}, concurrency)
+	)
+	add := func(found []*attachmentRecord, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		for _, r := range found {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				records = append(records, r)
+			}
+		}
+	}
+
+	for _, t := range threads {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t api.Thread) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			add(threadAttachments(client, t))
+		}(t)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	conversations, err := client.GetConversations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	for _, conv := range conversations {
+		if conv.WorkspaceID != workspaceID {
+			continue
+		}
+		found, err := conversationAttachments(client, conv.ID)
+		if err != nil {
+			return nil, err
+		}
+		add(found, nil)
+	}
+
+	return records, nil
+}
+
+func threadAttachments(client *api.Client, t api.Thread) ([]*attachmentRecord, error) {
+	var found []*attachmentRecord
+	if t.CommentCount > 0 {
+		comments, err := client.GetComments(t.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comments of thread %d: %w", t.ID, err)
+		}
+		for _, c := range comments {
+			attachments, err := client.GetAttachments("comment", c.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get attachments of comment %d: %w", c.ID, err)
+			}
+			for _, a := range attachments {
+				found = append(found, &attachmentRecord{Attachment: a, ChannelID: t.ChannelID,
+					ThreadID: t.ID, CommentID: c.ID, Uploader: c.Creator})
+			}
+		}
+	}
+
+	attachments, err := client.GetAttachments("thread", t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments of thread %d: %w", t.ID, err)
+	}
+	for _, a := range attachments {
+		found = append(found, &attachmentRecord{Attachment: a, ChannelID: t.ChannelID,
+			ThreadID: t.ID, Uploader: t.Creator})
+	}
+	return found, nil
+}
+
+func conversationAttachments(client *api.Client, conversationID int) ([]*attachmentRecord, error) {
+	attachments, err := client.GetAttachments("conversation", conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments of conversation %d: %w", conversationID, err)
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	messages, err := client.GetConversationMessages(conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages of conversation %d: %w", conversationID, err)
+	}
+	uploaders := make(map[int]int)
+	for _, m := range messages {
+		for _, a := range m.Attachments {
+			uploaders[a.ID] = m.UserID
+		}
+	}
+
+	var found []*attachmentRecord
+	for _, a := range attachments {
+		found = append(found, &attachmentRecord{Attachment: a, ConversationID: conversationID,
+			Uploader: uploaders[a.ID]})
+	}
+	return found, nil
+}
+
+// findDuplicates checksums every attachment that shares its size with
+// another one and marks each copy with the ID of the earliest upload.
+func findDuplicates(client *api.Client, records []*attachmentRecord, concurrency int) error {
+	bySize := make(map[int64][]*attachmentRecord)
+	for _, r := range records {
+		if r.Size > 0 {
+			bySize[r.Size] = append(bySize[r.Size], r)
+		}
+	}
+	var candidates []*attachmentRecord
+	var total int64
+	for _, group := range bySize {
+		if len(group) > 1 {
+			candidates = append(candidates, group...)
+			total += int64(len(group)) * group[0].Size
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	bar := progress.New(os.Stderr, fmt.Sprintf("Checksumming %d files", len(candidates)), total)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, r := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *attachmentRecord) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sum, err := attachmentChecksum(client, r, bar)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to checksum %s: %w", r.Title, err)
+				}
+				return
+			}
+			r.Checksum = sum
+		}(r)
+	}
+	wg.Wait()
+	bar.Finish()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	byChecksum := make(map[string][]*attachmentRecord)
+	for _, r := range candidates {
+		byChecksum[r.Checksum] = append(byChecksum[r.Checksum], r)
+	}
+	for _, group := range byChecksum {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].UploadedTS != group[j].UploadedTS {
+				return group[i].UploadedTS < group[j].UploadedTS
+			}
+			return group[i].ID < group[j].ID
+		})
+		for _, r := range group[1:] {
+			r.DuplicateOf = group[0].ID
+		}
+	}
+	return nil
+}
+
+func attachmentChecksum(client *api.Client, r *attachmentRecord, bar *progress.Bar) (string, error) {
+	body, err := client.OpenAttachment(&r.Attachment)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(h, bar), body); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (r *attachmentRecord) location(dir *directory) string {
+	if r.ConversationID != 0 {
+		return "Direct messages"
+	}
+	return dir.channelName(r.ChannelID)
+}
+
+func (r *attachmentRecord) uploaderName(dir *directory) string {
+	if r.Uploader == 0 {
+		return "unknown"
+	}
+	return dir.userName(r.Uploader)
+}
+
+func ageBucket(uploadedTS int64) string {
+	age := time.Since(time.Unix(uploadedTS, 0))
+	switch {
+	case uploadedTS == 0:
+		return "unknown"
+	case age < 30*24*time.Hour:
+		return "under 30 days"
+	case age < 90*24*time.Hour:
+		return "30-90 days"
+	case age < 365*24*time.Hour:
+		return "90 days-1 year"
+	}
+	return "over 1 year"
+}
+
+type usage struct {
+	key   string
+	count int
+	size  int64
+}
+
+// tally sums attachment counts and sizes by key, largest first.
+func tally(records []*attachmentRecord, key func(*attachmentRecord) string) []usage {
+	byKey := make(map[string]*usage)
+	for _, r := range records {
+		k := key(r)
+		if byKey[k] == nil {
+			byKey[k] = &usage{key: k}
+		}
+		byKey[k].count++
+		byKey[k].size += r.Size
+	}
+	var out []usage
+	for _, u := range byKey {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].size != out[j].size {
+			return out[i].size > out[j].size
+		}
+		return out[i].key < out[j].key
+	})
+	return out
+}
+
+func printAttachmentReport(dir *directory, records []*attachmentRecord, top int) {
+	if len(records) == 0 {
+		fmt.Println("No attachments found.")
+		return
+	}
+
+	var total int64
+	for _, r := range records {
+		total += r.Size
+	}
+	fmt.Printf("%d attachments, %s in total\n", len(records), progress.FormatBytes(total))
+
+	sections := []struct {
+		title string
+		key   func(*attachmentRecord) string
+	}{
+		{"CHANNEL", func(r *attachmentRecord) string { return r.location(dir) }},
+		{"UPLOADER", func(r *attachmentRecord) string { return r.uploaderName(dir) }},
+		{"TYPE", func(r *attachmentRecord) string {
+			if r.MimeType == "" {
+				return "unknown"
+			}
+			return r.MimeType
+		}},
+		{"AGE", func(r *attachmentRecord) string { return ageBucket(r.UploadedTS) }},
+	}
+	for _, s := range sections {
+		rows := tally(records, s.key)
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "%s\tFILES\tSIZE\n", s.title)
+		for i, u := range rows {
+			if top > 0 && i == top {
+				fmt.Fprintf(w, "(%d more)\t\t\n", len(rows)-top)
+				break
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\n", u.key, u.count, progress.FormatBytes(u.size))
+		}
+		w.Flush()
+	}
+
+	copies := make(map[int][]*attachmentRecord)
+	var wasted int64
+	for _, r := range records {
+		if r.DuplicateOf != 0 {
+			copies[r.DuplicateOf] = append(copies[r.DuplicateOf], r)
+			wasted += r.Size
+		}
+	}
+	fmt.Println()
+	if len(copies) == 0 {
+		fmt.Println("No duplicate attachments found.")
+		return
+	}
+
+	var originals []*attachmentRecord
+	for _, r := range records {
+		if len(copies[r.ID]) > 0 {
+			originals = append(originals, r)
+		}
+	}
+	sort.Slice(originals, func(i, j int) bool {
+		wi := int64(len(copies[originals[i].ID])) * originals[i].Size
+		wj := int64(len(copies[originals[j].ID])) * originals[j].Size
+		if wi != wj {
+			return wi > wj
+		}
+		return originals[i].ID < originals[j].ID
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "DUPLICATE\tCOPIES\tSIZE\tWASTED\tIDS")
+	for i, r := range originals {
+		if top > 0 && i == top {
+			fmt.Fprintf(w, "(%d more)\t\t\t\t\n", len(originals)-top)
+			break
+		}
+		ids := strconv.Itoa(r.ID)
+		for _, c := range copies[r.ID] {
+			ids += "," + strconv.Itoa(c.ID)
+		}
+		n := len(copies[r.ID])
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", r.Title, n+1, progress.FormatBytes(r.Size),
+			progress.FormatBytes(int64(n)*r.Size), ids)
+	}
+	w.Flush()
+	fmt.Printf("\n%s could be freed by removing duplicates\n", progress.FormatBytes(wasted))
+}
+
+func writeAttachmentCSV(out io.Writer, dir *directory, records []*attachmentRecord) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"id", "title", "mime_type", "size", "uploaded", "channel", "thread_id",
+		"comment_id", "conversation_id", "uploader", "sha256", "duplicate_of", "url"})
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	optional := func(id int) string {
+		if id == 0 {
+			return ""
+		}
+		return strconv.Itoa(id)
+	}
+	for _, r := range records {
+		uploaded := ""
+		if r.UploadedTS != 0 {
+			uploaded = time.Unix(r.UploadedTS, 0).Format(time.RFC3339)
+		}
+		w.Write([]string{strconv.Itoa(r.ID), r.Title, r.MimeType, strconv.FormatInt(r.Size, 10),
+			uploaded, r.location(dir), optional(r.ThreadID), optional(r.CommentID),
+			optional(r.ConversationID), r.uploaderName(dir), r.Checksum,
+			optional(r.DuplicateOf), r.URL})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	attachmentsReportCmd.Flags().StringVar(&reportFormatFlag, "format", "table", "Output format: table or csv")
+	attachmentsReportCmd.Flags().IntVar(&reportTopFlag, "top", 10, "Rows to show per table (0 for all)")
+	attachmentsReportCmd.Flags().IntVar(&reportConcurrencyFlag, "concurrency", 4, "Number of requests to make at once")
+
+	attachmentsCmd.AddCommand(attachmentsReportCmd)
+}
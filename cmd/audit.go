@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditLimitFlag    int
+	auditSinceFlag    string
+	auditEndpointFlag string
+	auditProfileFlag  string
+	auditFailedFlag   bool
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the local log of changes made through the CLI",
+	Long: `Every create, update, delete, membership change and reaction sent by the CLI
+is appended to audit.jsonl in the config directory, with its time, the
+profile of the token used (a short hash of it, as in token:1a2b3c4d5e6f),
+endpoint, a hash of the payload and the result. 'twist retention apply' also
+adds a RETENTION entry per thread naming the rule that selected it. Dry runs
+are not recorded.`,
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded changes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := audit.DefaultPath()
+		if err != nil {
+			return err
+		}
+		entries, err := audit.Read(path)
+		if err != nil {
+			return err
+		}
+
+		var since time.Time
+		if auditSinceFlag != "" {
+			since, err = time.ParseInLocation("2006-01-02", auditSinceFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --since date (use YYYY-MM-DD): %w", err)
+			}
+		}
+
+		type numbered struct {
+			n int
+			e audit.Entry
+		}
+		var shown []numbered
+		for i, e := range entries {
+			if e.Time.Before(since) ||
+				(auditEndpointFlag != "" && !strings.Contains(e.Endpoint, auditEndpointFlag)) ||
+				(auditProfileFlag != "" && e.Profile != auditProfileFlag) ||
+				(auditFailedFlag && e.Result == "ok") {
+				continue
+			}
+			shown = append(shown, numbered{i + 1, e})
+		}
+		if auditLimitFlag > 0 && len(shown) > auditLimitFlag {
+			shown = shown[len(shown)-auditLimitFlag:]
+		}
+
+		if len(shown) == 0 {
+			fmt.Println("No audit entries found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tTIME\tPROFILE\tMETHOD\tENDPOINT\tSTATUS\tRESULT")
+		for _, s := range shown {
+			status := "-"
+			if s.e.Status != 0 {
+				status = strconv.Itoa(s.e.Status)
+			}
+			profile := s.e.Profile
+			if profile == "" {
+				profile = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", s.n, s.e.Time.Local().Format("2006-01-02 15:04:05"),
+				profile, s.e.Method, s.e.Endpoint, status, s.e.Result)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var auditShowCmd = &cobra.Command{
+	Use:   "show [number]",
+	Short: "Show one recorded change",
+	Long:  `Show the details of an audit entry by the number printed by 'twist audit list'.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid entry number: %w", err)
+		}
+
+		path, err := audit.DefaultPath()
+		if err != nil {
+			return err
+		}
+		entries, err := audit.Read(path)
+		if err != nil {
+			return err
+		}
+		if n < 1 || n > len(entries) {
+			return fmt.Errorf("no audit entry %d (the log has %d)", n, len(entries))
+		}
+		e := entries[n-1]
+
+		fmt.Printf("Entry:    %d\n", n)
+		fmt.Printf("Time:     %s\n", e.Time.Local().Format(time.RFC3339))
+		if e.Profile != "" {
+			fmt.Printf("Profile:  %s\n", e.Profile)
+		}
+		fmt.Printf("Request:  %s %s\n", e.Method, e.Endpoint)
+		if e.PayloadHash != "" {
+			fmt.Printf("Payload:  %s (%d bytes)\n", e.PayloadHash, e.PayloadSize)
+		}
+		if e.Status != 0 {
+			fmt.Printf("Status:   %d\n", e.Status)
+		}
+		fmt.Printf("Result:   %s\n", e.Result)
+		if e.Error != "" {
+			fmt.Printf("Error:    %s\n", e.Error)
+		}
+		if e.Note != "" {
+			fmt.Printf("Note:     %s\n", e.Note)
+		}
+		if e.DurationMS != 0 {
+			fmt.Printf("Duration: %dms\n", e.DurationMS)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	auditListCmd.Flags().IntVar(&auditLimitFlag, "limit", 50, "Show only the most recent entries (0 for all)")
+	auditListCmd.Flags().StringVar(&auditSinceFlag, "since", "", "Only entries on or after this date (YYYY-MM-DD)")
+	auditListCmd.Flags().StringVar(&auditEndpointFlag, "endpoint", "", "Only entries whose endpoint contains this text")
+	auditListCmd.Flags().StringVar(&auditProfileFlag, "profile", "", "Only entries made with this profile (as shown in the list)")
+	auditListCmd.Flags().BoolVar(&auditFailedFlag, "failed", false, "Only failed requests")
+
+	auditCmd.AddCommand(auditListCmd)
+	auditCmd.AddCommand(auditShowCmd)
+}
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/filter"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bulkChannelFlag     string
+	bulkWorkspaceFlag   int
+	bulkWhereFlag       string
+	bulkAllFlag         bool
+	bulkToFlag          string
+	bulkConcurrencyFlag int
+)
+
+const threadPageSize = 100
+
+var bulkActions = map[string]string{
+	"archive":   "Archive",
+	"unarchive": "Unarchive",
+	"star":      "Star",
+	"pin":       "Pin",
+	"delete":    "Delete",
+	"move":      "Move",
+}
+
+var threadsBulkCmd = &cobra.Command{
+	Use:   "bulk [archive|unarchive|star|pin|delete|move]",
+	Short: "Apply an action to every thread matching a filter",
+	Long: `Apply an action to the threads of a channel that match a --where expression.
+The matching threads are listed first and you are asked to confirm unless
+--yes is given. Use --dry-run to only list them. delete and move need either
+--where or --all, so that a whole channel is never changed by accident.
+
+  twist threads bulk archive --channel general --workspace 123 \
+      --where 'last_updated < 90d && comment_count == 0'
+  twist threads bulk move --channel 456 --to 789 --where 'title ~ "[ops]"'
+
+Fields: id, title, content, author, bot, creator, channel, channel_id,
+posted, last_updated, comment_count, participants, starred, pinned and
+archived.
+Expressions support ==, !=, <, <=, >, >=, ~ (contains), !~, &&, ||, ! and
+parentheses. Timestamps compare with dates (2024-01-31) or ages (90d, 12h,
+2w): 'last_updated < 90d' means last updated more than 90 days ago.
+
+Channel names need --workspace; channel IDs do not.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := args[0]
+		verb, ok := bulkActions[action]
+		if !ok {
+			return fmt.Errorf("invalid action %q: must be archive, unarchive, star, pin, delete or move", action)
+		}
+		if bulkChannelFlag == "" {
+			return fmt.Errorf("--channel is required")
+		}
+		if action == "move" && bulkToFlag == "" {
+			return fmt.Errorf("--to is required for move")
+		}
+		if bulkConcurrencyFlag < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+
+		if bulkAllFlag && bulkWhereFlag != "" {
+			return fmt.Errorf("--all and --where cannot be combined")
+		}
+		if (action == "delete" || action == "move") && !bulkAllFlag && bulkWhereFlag == "" {
+			return fmt.Errorf("%s needs --where, or --all to select every thread in the channel", action)
+		}
+
+		var f *filter.Filter
+		if bulkWhereFlag != "" {
+			var err error
+			if f, err = filter.Parse(bulkWhereFlag); err != nil {
+				return fmt.Errorf("invalid --where: %w", err)
+			}
+			if err := f.Check(threadFields()); err != nil {
+				return fmt.Errorf("invalid --where: %w", err)
+			}
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+
+		channelID, err := strconv.Atoi(strings.TrimPrefix(bulkChannelFlag, "#"))
+		if err != nil && bulkWorkspaceFlag == 0 {
+			return fmt.Errorf("--workspace is required to look up channel %q by name", bulkChannelFlag)
+		}
+		workspaceID := bulkWorkspaceFlag
+		if workspaceID == 0 {
+			channel, err := client.GetChannel(channelID)
+			if err != nil {
+				return fmt.Errorf("failed to get channel: %w", err)
+			}
+			workspaceID = channel.WorkspaceID
+		}
+
+		dir, err := loadDirectory(client, workspaceID)
+		if err != nil {
+			return err
+		}
+		if channelID, err = dir.findChannel(bulkChannelFlag); err != nil {
+			return err
+		}
+
+		var destID int
+		if action == "move" {
+			if destID, err = dir.findChannel(bulkToFlag); err != nil {
+				return fmt.Errorf("invalid --to: %w", err)
+			}
+		}
+
+		threads, err := allThreads(client, channelID)
+		if err != nil {
+			return fmt.Errorf("failed to get threads: %w", err)
+		}
+
+		var matched []api.Thread
+		for _, t := range threads {
+			if f != nil {
+				ok, err := f.Match(threadRecord(t, dir))
+				if err != nil {
+					return fmt.Errorf("invalid --where: %w", err)
+				}
+				if !ok {
+					continue
+				}
+			}
+			matched = append(matched, t)
+		}
+
+		if len(matched) == 0 {
+			fmt.Printf("No threads in #%s match.\n", dir.channelName(channelID))
+			return nil
+		}
+
+		printThreadPreview(matched, dir)
+		fmt.Printf("\n%d of %d thread(s) in #%s match\n", len(matched), len(threads), dir.channelName(channelID))
+
+		if dryRunFlag {
+			return nil
+		}
+		prompt := fmt.Sprintf("%s %d thread(s)?", verb, len(matched))
+		if action == "move" {
+			prompt = fmt.Sprintf("Move %d thread(s) to #%s?", len(matched), dir.channelName(destID))
+		}
+		if err := confirmAction(prompt); err != nil {
+			return err
+		}
+
+		apply := func(t api.Thread) error {
+			switch action {
+			case "archive":
+				return client.ArchiveThread(t.ID)
+			case "unarchive":
+				return client.UnarchiveThread(t.ID)
+			case "star":
+				return client.StarThread(t.ID)
+			case "pin":
+				return client.PinThread(t.ID)
+			case "delete":
+				return client.DeleteThread(t.ID)
+			case "move":
+				_, err := client.UpdateThread(t.ID, map[string]interface{}{"channel_id": destID})
+				return err
+			}
+			return nil
+		}
+
+		failures := runBulk(matched, bulkConcurrencyFlag, apply)
+
+		fmt.Printf("%d succeeded, %d failed\n", len(matched)-len(failures), len(failures))
+		if len(failures) > 0 {
+			for _, t := range matched {
+				if err, ok := failures[t.ID]; ok {
+					fmt.Fprintf(os.Stderr, "  thread %d: %v\n", t.ID, err)
+				}
+			}
+			return fmt.Errorf("%d of %d thread(s) failed", len(failures), len(matched))
+		}
+		return nil
+	},
+}
+
+// threadRecord exposes a thread to --where expressions.
+func threadRecord(t api.Thread, dir *directory) filter.Record {
+	return filter.Record{
+		"id":            t.ID,
+		"title":         t.Title,
+		"content":       t.Content,
+		"author":        dir.userName(t.Creator),
+		"bot":           dir.users[t.Creator].Bot,
+		"creator":       t.Creator,
+		"channel":       dir.channelName(t.ChannelID),
+		"channel_id":    t.ChannelID,
+		"posted":        time.Unix(t.PostedTS, 0),
+		"last_updated":  time.Unix(t.LastUpdatedTS, 0),
+		"comment_count": t.CommentCount,
+		"participants":  len(t.Participants),
+		"starred":       t.Starred,
+		"pinned":        t.Pinned,
+		"archived":      t.Archived,
+	}
+}
+
+// threadFields lists the fields of threadRecord, for checking expressions
+// before any thread is matched.
+func threadFields() filter.Record {
+	return threadRecord(api.Thread{}, &directory{})
+}
+
+// allThreads pages through every thread of a channel, newest first. The
+// oldest threads, which filters such as 'last_updated < 90d' are after, are
+// the ones a single request leaves out.
+func allThreads(client *api.Client, channelID int) ([]api.Thread, error) {
+	var threads []api.Thread
+	seen := make(map[int]bool)
+	opts := map[string]interface{}{"limit": threadPageSize}
+	for {
+		page, err := client.GetThreadsPage(channelID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		var oldest int64
+		for _, t := range page {
+			if oldest == 0 || t.LastUpdatedTS < oldest {
+				oldest = t.LastUpdatedTS
+			}
+			if seen[t.ID] {
+				continue
+			}
+			seen[t.ID] = true
+			added++
+			threads = append(threads, t)
+		}
+
+		// Threads sharing the oldest timestamp of a full page may be split
+		// across pages, so ask for that second again and rely on seen.
+		if added == 0 || len(page) < threadPageSize {
+			return threads, nil
+		}
+		opts["older_than_ts"] = oldest + 1
+	}
+}
+
+func printThreadPreview(threads []api.Thread, dir *directory) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tAUTHOR\tLAST UPDATED\tCOMMENTS")
+	for _, t := range threads {
+		title := t.Title
+		if len(title) > 50 {
+			title = title[:47] + "..."
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\n", t.ID, title, dir.userName(t.Creator),
+			time.Unix(t.LastUpdatedTS, 0).Format("2006-01-02"), t.CommentCount)
+	}
+	w.Flush()
+}
+
+// runBulk applies fn to each thread with at most concurrency calls in
+// flight and returns the errors by thread ID.
+func runBulk(threads []api.Thread, concurrency int, fn func(api.Thread) error) map[int]error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures = make(map[int]error)
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, t := range threads {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t api.Thread) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(t); err != nil {
+				mu.Lock()
+				failures[t.ID] = err
+				mu.Unlock()
+			}
+		}(t)
+	}
+	wg.Wait()
+	return failures
+}
+
+func init() {
+	threadsBulkCmd.Flags().StringVar(&bulkChannelFlag, "channel", "", "Channel ID or name to operate on")
+	threadsBulkCmd.Flags().IntVar(&bulkWorkspaceFlag, "workspace", 0, "Workspace ID (needed for channel names)")
+	threadsBulkCmd.Flags().StringVar(&bulkWhereFlag, "where", "", "Filter expression selecting threads")
+	threadsBulkCmd.Flags().BoolVar(&bulkAllFlag, "all", false, "Select every thread in the channel")
+	threadsBulkCmd.Flags().StringVar(&bulkToFlag, "to", "", "Destination channel ID or name for move")
+	threadsBulkCmd.Flags().IntVar(&bulkConcurrencyFlag, "concurrency", 4, "Number of threads to process at once")
+
+	threadsCmd.AddCommand(threadsBulkCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+// testClient returns a client whose requests are served by h.
+func testClient(t *testing.T, h http.HandlerFunc) *api.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	client := api.NewClient("test-token")
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return redirectTransport{target: target, next: next}
+	})
+	return client
+}
+
+func TestAllThreads(t *testing.T) {
+	// 250 threads, several sharing each timestamp so that pages split
+	// threads updated in the same second.
+	var threads []api.Thread
+	for i := 0; i < 250; i++ {
+		threads = append(threads, api.Thread{ID: i + 1, ChannelID: 7, LastUpdatedTS: int64(10000 - i/3)})
+	}
+
+	requests := 0
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		older, _ := strconv.ParseInt(q.Get("older_than_ts"), 10, 64)
+		var page []api.Thread
+		for _, th := range threads {
+			if older != 0 && th.LastUpdatedTS >= older {
+				continue
+			}
+			if len(page) == limit {
+				break
+			}
+			page = append(page, th)
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+
+	got, err := allThreads(client, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(threads) {
+		t.Fatalf("allThreads returned %d threads, want %d", len(got), len(threads))
+	}
+	ids := make([]int, len(got))
+	for i, th := range got {
+		ids[i] = th.ID
+	}
+	sort.Ints(ids)
+	for i, id := range ids {
+		if id != i+1 {
+			t.Fatalf("thread %d missing or repeated", i+1)
+		}
+	}
+	if requests < 3 {
+		t.Errorf("made %d requests, want at least 3 pages", requests)
+	}
+}
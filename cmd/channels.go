@@ -8,7 +8,6 @@ import (
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
-	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
@@ -44,7 +43,7 @@ var channelsListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		channels, err := client.GetChannels(workspaceID, archivedFlag)
 		if err != nil {
 			return fmt.Errorf("failed to get channels: %w", err)
@@ -83,7 +82,7 @@ var channelsShowCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		channel, err := client.GetChannel(channelID)
 		if err != nil {
 			return fmt.Errorf("failed to get channel: %w", err)
@@ -151,7 +150,7 @@ var channelsCreateCmd = &cobra.Command{
 			}
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		channel, err := client.CreateChannel(workspaceID, name, opts)
 		if err != nil {
 			return fmt.Errorf("failed to create channel: %w", err)
@@ -199,7 +198,7 @@ var channelsUpdateCmd = &cobra.Command{
 			return fmt.Errorf("no updates specified; use flags like --name, --description, --color, or --public")
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		channel, err := client.UpdateChannel(channelID, updates)
 		if err != nil {
 			return fmt.Errorf("failed to update channel: %w", err)
@@ -229,7 +228,7 @@ var channelsArchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.ArchiveChannel(channelID); err != nil {
 			return fmt.Errorf("failed to archive channel: %w", err)
 		}
@@ -255,7 +254,7 @@ var channelsUnarchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnarchiveChannel(channelID); err != nil {
 			return fmt.Errorf("failed to unarchive channel: %w", err)
 		}
@@ -281,7 +280,11 @@ var channelsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		if err := confirmAction(fmt.Sprintf("Delete channel %d and all of its threads?", channelID)); err != nil {
+			return err
+		}
+
+		client := newClient(token)
 		if err := client.DeleteChannel(channelID); err != nil {
 			return fmt.Errorf("failed to delete channel: %w", err)
 		}
@@ -312,7 +315,7 @@ var channelsAddUserCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.AddChannelUser(channelID, userID); err != nil {
 			return fmt.Errorf("failed to add user to channel: %w", err)
 		}
@@ -343,7 +346,7 @@ var channelsRemoveUserCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.RemoveChannelUser(channelID, userID); err != nil {
 			return fmt.Errorf("failed to remove user from channel: %w", err)
 		}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/intelligrit/twist-cli/internal/audit"
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+// newClient returns an API client that honours the global --dry-run flag
+// and records every change it makes in the audit log.
+func newClient(token string) *api.Client {
+	client := api.NewClient(token)
+	if dryRunFlag {
+		client.SetDryRun(os.Stdout)
+		return client
+	}
+
+	path, err := audit.DefaultPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: audit log disabled: %v\n", err)
+		return client
+	}
+	base, _ := url.Parse(api.BaseURL)
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return &audit.Transport{
+			Next:     next,
+			Path:     path,
+			BasePath: base.Path,
+			Profile:  audit.Profile(token),
+			Warn:     os.Stderr,
+		}
+	})
+	return client
+}
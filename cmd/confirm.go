@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Anything but y or yes, including end of input, counts as no.
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// confirmAction asks before a destructive change. It does not ask with
+// --yes, or with --dry-run since nothing will be changed.
+func confirmAction(prompt string) error {
+	if yesFlag || dryRunFlag {
+		return nil
+	}
+	if !confirm(prompt) {
+		return fmt.Errorf("aborted (use --yes to skip this prompt)")
+	}
+	return nil
+}
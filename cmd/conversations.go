@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
@@ -13,6 +15,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	conversationToFlag        string
+	conversationTitleFlag     string
+	conversationWorkspaceFlag int
+	conversationEditorFlag    bool
+	conversationLastFlag      int
+	conversationUnreadFlag    bool
+	conversationArchivedFlag  bool
+	conversationMutedFlag     bool
+	conversationWithFlag      string
+)
+
 var conversationsCmd = &cobra.Command{
 	Use:   "conversations",
 	Short: "Manage direct message conversations",
@@ -22,37 +36,115 @@ var conversationsCmd = &cobra.Command{
 var conversationsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all conversations",
-	Long:  `List all direct message conversations.`,
+	Long: `List direct message conversations, most recently active first. Archived
+conversations are only shown with --archived, and --workspace limits the list
+to one workspace.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := auth.GetToken(tokenFlag)
 		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		conversations, err := client.GetConversations()
 		if err != nil {
 			return fmt.Errorf("failed to get conversations: %w", err)
 		}
 
-		if len(conversations) == 0 {
+		unreadList, err := client.GetUnreadConversations()
+		if err != nil {
+			return fmt.Errorf("failed to get unread conversations: %w", err)
+		}
+		unread := make(map[int]int)
+		for _, u := range unreadList {
+			unread[u.ConversationID] = u.ObjIndex
+		}
+
+		me, err := client.GetSessionUser()
+		if err != nil {
+			return fmt.Errorf("failed to get current user: %w", err)
+		}
+
+		unreadCount := func(conv api.Conversation) int {
+			lastRead, ok := unread[conv.ID]
+			if !ok || conv.LastObjIndex <= lastRead {
+				return 0
+			}
+			return conv.LastObjIndex - lastRead
+		}
+
+		var shown []api.Conversation
+		var workspaceIDs []int
+		for _, conv := range conversations {
+			if conv.IsArchived != conversationArchivedFlag ||
+				(conversationWorkspaceFlag != 0 && conv.WorkspaceID != conversationWorkspaceFlag) ||
+				(conversationMutedFlag && !conv.IsMuted) ||
+				(conversationUnreadFlag && unreadCount(conv) == 0) {
+				continue
+			}
+			shown = append(shown, conv)
+			if !slices.Contains(workspaceIDs, conv.WorkspaceID) {
+				workspaceIDs = append(workspaceIDs, conv.WorkspaceID)
+			}
+		}
+
+		if len(shown) == 0 {
 			fmt.Println("No conversations found.")
 			return nil
 		}
 
+		// Names only need looking up in the workspaces of the conversations
+		// being listed.
+		dir, err := loadDirectory(client, workspaceIDs...)
+		if err != nil {
+			return err
+		}
+
+		if conversationWithFlag != "" {
+			withID, err := dir.findUser(conversationWithFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --with: %w", err)
+			}
+			shown = slices.DeleteFunc(shown, func(conv api.Conversation) bool {
+				return !slices.Contains(conv.UserIDs, withID)
+			})
+			if len(shown) == 0 {
+				fmt.Println("No conversations found.")
+				return nil
+			}
+		}
+		sort.SliceStable(shown, func(i, j int) bool { return shown[i].LastActiveTS > shown[j].LastActiveTS })
+
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tPARTICIPANTS\tMESSAGES\tCREATED")
-		fmt.Fprintln(w, "--\t------------\t--------\t-------")
-		for _, conv := range conversations {
-			created := time.Unix(conv.CreatedTS, 0).Format("2006-01-02")
-			userIDsStr := ""
-			for i, uid := range conv.UserIDs {
-				if i > 0 {
-					userIDsStr += ", "
+		fmt.Fprintln(w, "ID\tPARTICIPANTS\tUNREAD\tLAST MESSAGE\tPREVIEW")
+		fmt.Fprintln(w, "--\t------------\t------\t------------\t-------")
+		for _, conv := range shown {
+			var names []string
+			for _, uid := range conv.UserIDs {
+				if uid != me.ID {
+					names = append(names, dir.userName(uid))
 				}
-				userIDsStr += fmt.Sprintf("%d", uid)
 			}
-			fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", conv.ID, userIDsStr, conv.MessageCount, created)
+			participants := strings.Join(names, ", ")
+			if conv.Title != "" {
+				participants = conv.Title + " (" + participants + ")"
+			}
+			if conv.IsMuted {
+				participants += " [muted]"
+			}
+
+			unreadStr := ""
+			if n := unreadCount(conv); n > 0 {
+				unreadStr = strconv.Itoa(n)
+			}
+
+			lastActive := time.Unix(conv.LastActiveTS, 0).Format("2006-01-02 15:04")
+			snippet := preview(conv.Snippet, 50)
+			if snippet != "" && conv.SnippetBy != 0 {
+				snippet = dir.userName(conv.SnippetBy) + ": " + snippet
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", conv.ID, participants, unreadStr, lastActive, snippet)
 		}
 		w.Flush()
 
@@ -76,7 +168,7 @@ var conversationsShowCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		messages, err := client.GetConversationMessages(conversationID)
 		if err != nil {
 			return fmt.Errorf("failed to get messages: %w", err)
@@ -104,31 +196,67 @@ var conversationsShowCmd = &cobra.Command{
 var conversationsSendCmd = &cobra.Command{
 	Use:   "send [user-id] [message...]",
 	Short: "Send a direct message",
-	Long:  `Send a direct message to a user by user ID.`,
-	Args:  cobra.MinimumNArgs(2),
+	Long: `Send a direct message to a user by user ID, or with --to to several users at
+once, creating or reusing the group conversation with exactly those users.
+--to takes comma-separated IDs, names or emails; use --workspace to look
+names up in one workspace. @name, @email and @group mentions in the message
+become Twist mentions. Use --attach to include files with the message.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		userID, err := strconv.Atoi(args[0])
+		token, err := auth.GetToken(tokenFlag)
 		if err != nil {
-			return fmt.Errorf("invalid user ID: %w", err)
+			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		content := strings.Join(args[1:], " ")
+		client := newClient(token)
 
-		token, err := auth.GetToken(tokenFlag)
+		var userIDs []int
+		if conversationToFlag != "" {
+			userIDs, err = resolveUserRefs(client, conversationWorkspaceFlag, []string{conversationToFlag})
+			if err != nil {
+				return err
+			}
+		} else {
+			if len(args) < 2 {
+				return fmt.Errorf("expected a user ID and a message (or --to)")
+			}
+			userID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+			userIDs = []int{userID}
+			args = args[1:]
+		}
+
+		content := strings.Join(args, " ")
+
+		attachPaths, err := checkAttachPaths(attachFlag)
 		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+			return err
 		}
 
-		client := api.NewClient(token)
+		// Mentions are resolved and files uploaded before the conversation is
+		// created, so that a failure leaves no empty conversation behind.
+		content, recipients, err := resolveMessage(client, func() (messageTarget, error) {
+			return messageTarget{WorkspaceID: conversationWorkspaceFlag}, nil
+		}, content, "")
+		if err != nil {
+			return err
+		}
+
+		attachments, err := uploadAttachments(client, attachPaths)
+		if err != nil {
+			return err
+		}
 
 		// Get or create conversation
-		conversation, err := client.GetOrCreateConversation([]int{userID})
+		conversation, err := client.GetOrCreateConversation(userIDs)
 		if err != nil {
 			return fmt.Errorf("failed to create conversation: %w", err)
 		}
 
 		// Send message
-		message, err := client.SendConversationMessage(conversation.ID, content, nil)
+		message, err := client.SendConversationMessage(conversation.ID, content, recipients, attachments...)
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -140,6 +268,136 @@ var conversationsSendCmd = &cobra.Command{
 	},
 }
 
+var conversationsCreateCmd = &cobra.Command{
+	Use:   "create [user...]",
+	Short: "Create a group conversation",
+	Long: `Create a conversation with the given users (IDs, names or emails), or reuse
+the existing one with exactly those users. Use --title to name it.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		userIDs, err := resolveUserRefs(client, conversationWorkspaceFlag, args)
+		if err != nil {
+			return err
+		}
+
+		conversation, err := client.GetOrCreateConversation(userIDs)
+		if err != nil {
+			return fmt.Errorf("failed to create conversation: %w", err)
+		}
+
+		if conversationTitleFlag != "" {
+			conversation, err = client.UpdateConversation(conversation.ID, map[string]interface{}{"title": conversationTitleFlag})
+			if err != nil {
+				return fmt.Errorf("failed to set conversation title: %w", err)
+			}
+		}
+
+		fmt.Printf("Conversation #%d with %d user(s)\n", conversation.ID, len(conversation.UserIDs))
+		if conversation.Title != "" {
+			fmt.Printf("Title: %s\n", conversation.Title)
+		}
+		return nil
+	},
+}
+
+var conversationsAddUserCmd = &cobra.Command{
+	Use:   "add-user [conversation-id] [user...]",
+	Short: "Add users to a conversation",
+	Long:  `Add users (IDs, names or emails) to a group conversation.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conversationID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid conversation ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		userIDs, err := resolveUserRefs(client, conversationWorkspaceFlag, args[1:])
+		if err != nil {
+			return err
+		}
+
+		if err := client.AddConversationUsers(conversationID, userIDs); err != nil {
+			return fmt.Errorf("failed to add users to conversation: %w", err)
+		}
+
+		fmt.Printf("Added %d user(s) to conversation %d\n", len(userIDs), conversationID)
+		return nil
+	},
+}
+
+var conversationsRemoveUserCmd = &cobra.Command{
+	Use:   "remove-user [conversation-id] [user...]",
+	Short: "Remove users from a conversation",
+	Long:  `Remove users (IDs, names or emails) from a group conversation.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conversationID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid conversation ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		userIDs, err := resolveUserRefs(client, conversationWorkspaceFlag, args[1:])
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveConversationUsers(conversationID, userIDs); err != nil {
+			return fmt.Errorf("failed to remove users from conversation: %w", err)
+		}
+
+		fmt.Printf("Removed %d user(s) from conversation %d\n", len(userIDs), conversationID)
+		return nil
+	},
+}
+
+var conversationsLeaveCmd = &cobra.Command{
+	Use:   "leave [conversation-id]",
+	Short: "Leave a conversation",
+	Long:  `Leave a group conversation. You stop receiving its messages until someone adds you again.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conversationID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid conversation ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		if err := confirmAction(fmt.Sprintf("Leave conversation %d?", conversationID)); err != nil {
+			return err
+		}
+
+		client := newClient(token)
+		if err := client.LeaveConversation(conversationID); err != nil {
+			return fmt.Errorf("failed to leave conversation: %w", err)
+		}
+
+		fmt.Printf("Left conversation %d\n", conversationID)
+		return nil
+	},
+}
+
 var conversationsArchiveCmd = &cobra.Command{
 	Use:   "archive [conversation-id]",
 	Short: "Archive a conversation",
@@ -156,7 +414,7 @@ var conversationsArchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.ArchiveConversation(conversationID); err != nil {
 			return fmt.Errorf("failed to archive conversation: %w", err)
 		}
@@ -182,7 +440,7 @@ var conversationsUnarchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnarchiveConversation(conversationID); err != nil {
 			return fmt.Errorf("failed to unarchive conversation: %w", err)
 		}
@@ -208,7 +466,7 @@ var conversationsMuteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.MuteConversation(conversationID); err != nil {
 			return fmt.Errorf("failed to mute conversation: %w", err)
 		}
@@ -234,7 +492,7 @@ var conversationsUnmuteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnmuteConversation(conversationID); err != nil {
 			return fmt.Errorf("failed to unmute conversation: %w", err)
 		}
@@ -260,7 +518,7 @@ var conversationsMarkReadCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.MarkConversationRead(conversationID); err != nil {
 			return fmt.Errorf("failed to mark conversation as read: %w", err)
 		}
@@ -286,7 +544,7 @@ var conversationsMarkUnreadCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.MarkConversationUnread(conversationID); err != nil {
 			return fmt.Errorf("failed to mark conversation as unread: %w", err)
 		}
@@ -296,10 +554,185 @@ var conversationsMarkUnreadCmd = &cobra.Command{
 	},
 }
 
+var conversationsEditCmd = &cobra.Command{
+	Use:   "edit [message-id] [content...]",
+	Short: "Edit a conversation message",
+	Long: `Replace the content of a conversation message. With --editor the current
+content is opened in $EDITOR instead of being given on the command line. With
+--last, edit your most recent message in the given conversation instead of
+naming a message ID.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if conversationLastFlag == 0 && len(args) == 0 {
+			return fmt.Errorf("expected a message ID (or --last)")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+
+		var message *api.ConversationMessage
+		if conversationLastFlag != 0 {
+			message, err = lastOwnMessage(client, conversationLastFlag)
+			if err != nil {
+				return err
+			}
+		} else {
+			messageID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid message ID: %w", err)
+			}
+			args = args[1:]
+			if conversationEditorFlag {
+				if message, err = client.GetConversationMessage(messageID); err != nil {
+					return fmt.Errorf("failed to get message: %w", err)
+				}
+			} else {
+				message = &api.ConversationMessage{ID: messageID}
+			}
+		}
+
+		var content string
+		switch {
+		case conversationEditorFlag:
+			if content, err = editText(message.Content); err != nil {
+				return err
+			}
+			if content == message.Content {
+				fmt.Println("Message unchanged.")
+				return nil
+			}
+		case len(args) > 0:
+			content = strings.Join(args, " ")
+		default:
+			return fmt.Errorf("expected the new content (or --editor)")
+		}
+
+		updated, err := client.UpdateConversationMessage(message.ID, content)
+		if err != nil {
+			return fmt.Errorf("failed to update message: %w", err)
+		}
+
+		fmt.Printf("Message %d updated successfully\n", updated.ID)
+		return nil
+	},
+}
+
+var conversationsDeleteMessageCmd = &cobra.Command{
+	Use:   "delete-message [message-id]",
+	Short: "Delete a conversation message",
+	Long: `Delete a conversation message permanently. With --last, delete your most
+recent message in the given conversation instead of naming a message ID.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (conversationLastFlag == 0) == (len(args) == 0) {
+			return fmt.Errorf("expected either a message ID or --last")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+
+		var messageID int
+		prompt := ""
+		if conversationLastFlag != 0 {
+			message, err := lastOwnMessage(client, conversationLastFlag)
+			if err != nil {
+				return err
+			}
+			messageID = message.ID
+			prompt = fmt.Sprintf("Delete your last message in conversation %d (%q)?", conversationLastFlag, preview(message.Content, 60))
+		} else {
+			if messageID, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("invalid message ID: %w", err)
+			}
+			prompt = fmt.Sprintf("Delete message %d?", messageID)
+		}
+
+		if err := confirmAction(prompt); err != nil {
+			return err
+		}
+
+		if err := client.DeleteConversationMessage(messageID); err != nil {
+			return fmt.Errorf("failed to delete message: %w", err)
+		}
+
+		fmt.Printf("Message %d deleted successfully\n", messageID)
+		return nil
+	},
+}
+
+// lastOwnMessage returns the most recent message the session user sent in a
+// conversation.
+func lastOwnMessage(client *api.Client, conversationID int) (*api.ConversationMessage, error) {
+	me, err := client.GetSessionUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	messages, err := client.GetConversationMessages(conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	var last *api.ConversationMessage
+	for i := range messages {
+		m := &messages[i]
+		if m.UserID != me.ID {
+			continue
+		}
+		if last == nil || m.CreatedTS > last.CreatedTS || (m.CreatedTS == last.CreatedTS && m.ID > last.ID) {
+			last = m
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("you have no messages in conversation %d", conversationID)
+	}
+	return last, nil
+}
+
+// preview shortens text to a single line of at most n runes.
+func preview(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return text
+}
+
 func init() {
+	conversationsSendCmd.Flags().StringVar(&conversationToFlag, "to", "", "Comma-separated recipients (IDs, names or emails)")
+	conversationsSendCmd.Flags().IntVar(&conversationWorkspaceFlag, "workspace", 0, "Workspace to look up recipient and mention names in")
+	conversationsSendCmd.Flags().StringArrayVar(&attachFlag, "attach", nil, "File to attach (repeatable, globs allowed)")
+	conversationsCreateCmd.Flags().StringVar(&conversationTitleFlag, "title", "", "Conversation title")
+	conversationsCreateCmd.Flags().IntVar(&conversationWorkspaceFlag, "workspace", 0, "Workspace to look up user names in")
+	conversationsAddUserCmd.Flags().IntVar(&conversationWorkspaceFlag, "workspace", 0, "Workspace to look up user names in")
+	conversationsRemoveUserCmd.Flags().IntVar(&conversationWorkspaceFlag, "workspace", 0, "Workspace to look up user names in")
+
+	conversationsListCmd.Flags().BoolVar(&conversationUnreadFlag, "unread", false, "Only conversations with unread messages")
+	conversationsListCmd.Flags().BoolVar(&conversationArchivedFlag, "archived", false, "Show archived conversations instead of active ones")
+	conversationsListCmd.Flags().BoolVar(&conversationMutedFlag, "muted", false, "Only muted conversations")
+	conversationsListCmd.Flags().IntVar(&conversationWorkspaceFlag, "workspace", 0, "Only conversations in this workspace")
+	conversationsListCmd.Flags().StringVar(&conversationWithFlag, "with", "", "Only conversations with this user (ID, name or email)")
+
+	conversationsEditCmd.Flags().BoolVar(&conversationEditorFlag, "editor", false, "Edit the message in $EDITOR")
+	conversationsEditCmd.Flags().IntVar(&conversationLastFlag, "last", 0, "Edit your last message in this conversation")
+	conversationsDeleteMessageCmd.Flags().IntVar(&conversationLastFlag, "last", 0, "Delete your last message in this conversation")
+
 	conversationsCmd.AddCommand(conversationsListCmd)
 	conversationsCmd.AddCommand(conversationsShowCmd)
 	conversationsCmd.AddCommand(conversationsSendCmd)
+	conversationsCmd.AddCommand(conversationsCreateCmd)
+	conversationsCmd.AddCommand(conversationsAddUserCmd)
+	conversationsCmd.AddCommand(conversationsRemoveUserCmd)
+	conversationsCmd.AddCommand(conversationsLeaveCmd)
+	conversationsCmd.AddCommand(conversationsEditCmd)
+	conversationsCmd.AddCommand(conversationsDeleteMessageCmd)
 	conversationsCmd.AddCommand(conversationsArchiveCmd)
 	conversationsCmd.AddCommand(conversationsUnarchiveCmd)
 	conversationsCmd.AddCommand(conversationsMuteCmd)
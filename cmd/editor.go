@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editText opens initial in $VISUAL or $EDITOR (vi by default) and returns
+// the saved text. An empty result is treated as cancelling.
+func editText(initial string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "twist-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// The editor setting may carry arguments, e.g. "code --wait".
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	text := strings.TrimRight(string(data), "\n")
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("aborted: empty message")
+	}
+	return text, nil
+}
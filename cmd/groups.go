@@ -8,7 +8,6 @@ import (
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
-	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +39,7 @@ var groupsListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		groups, err := client.GetGroups(workspaceID)
 		if err != nil {
 			return fmt.Errorf("failed to get groups: %w", err)
@@ -79,7 +78,7 @@ var groupsShowCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		group, err := client.GetGroup(groupID)
 		if err != nil {
 			return fmt.Errorf("failed to get group: %w", err)
@@ -138,7 +137,7 @@ var groupsCreateCmd = &cobra.Command{
 			}
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		group, err := client.CreateGroup(workspaceID, name, opts)
 		if err != nil {
 			return fmt.Errorf("failed to create group: %w", err)
@@ -180,7 +179,7 @@ var groupsUpdateCmd = &cobra.Command{
 			return fmt.Errorf("no updates specified; use flags like --name or --description")
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		group, err := client.UpdateGroup(groupID, updates)
 		if err != nil {
 			return fmt.Errorf("failed to update group: %w", err)
@@ -210,7 +209,11 @@ var groupsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		if err := confirmAction(fmt.Sprintf("Delete group %d?", groupID)); err != nil {
+			return err
+		}
+
+		client := newClient(token)
 		if err := client.DeleteGroup(groupID); err != nil {
 			return fmt.Errorf("failed to delete group: %w", err)
 		}
@@ -241,7 +244,7 @@ var groupsAddUserCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.AddGroupUser(groupID, userID); err != nil {
 			return fmt.Errorf("failed to add user to group: %w", err)
 		}
@@ -272,7 +275,7 @@ var groupsRemoveUserCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.RemoveGroupUser(groupID, userID); err != nil {
 			return fmt.Errorf("failed to remove user from group: %w", err)
 		}
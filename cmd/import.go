@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/importer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormatFlag        string
+	importUserMapFlag       string
+	importCheckpointFlag    string
+	importNoAttributionFlag bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [workspace-id] [source]",
+	Short: "Import a backup or Slack export into a workspace",
+	Long: `Import content into a workspace from a directory written by 'twist backup'
+or from a Slack export zip.
+
+Channels are created with CreateChannel, top-level messages become threads and
+replies become comments. Every created object is recorded in a checkpoint file
+so re-running the import skips content that was already imported. A checkpoint
+belongs to one source and workspace; use a different --checkpoint for another.
+
+Use --user-map to point at a JSON object mapping source user IDs or email
+addresses to Twist user IDs; users not in the map are matched by email.
+Use --dry-run to see what would be created without changing anything.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid workspace ID: %w", err)
+		}
+		sourcePath := args[1]
+
+		format := importFormatFlag
+		if format == "" {
+			if strings.HasSuffix(strings.ToLower(sourcePath), ".zip") {
+				format = "slack"
+			} else {
+				format = "backup"
+			}
+		}
+
+		var src *importer.Source
+		switch format {
+		case "backup":
+			src, err = importer.ReadBackup(sourcePath)
+		case "slack":
+			src, err = importer.ReadSlackExport(sourcePath)
+		default:
+			return fmt.Errorf("invalid format: must be 'backup' or 'slack'")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read source: %w", err)
+		}
+
+		source, err := filepath.Abs(sourcePath)
+		if err != nil {
+			return fmt.Errorf("invalid source path: %w", err)
+		}
+
+		opts := importer.Options{
+			WorkspaceID:    workspaceID,
+			Source:         source,
+			CheckpointPath: importCheckpointFlag,
+			DryRun:         dryRunFlag,
+			Attribution:    !importNoAttributionFlag,
+			Log:            os.Stderr,
+		}
+		if importUserMapFlag != "" {
+			opts.UserMap, err = importer.LoadUserMap(importUserMapFlag)
+			if err != nil {
+				return err
+			}
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		stats, err := importer.Run(client, src, opts)
+		if stats != nil {
+			verb := "Created"
+			if dryRunFlag {
+				verb = "Would create"
+			}
+			fmt.Printf("%s %d channel(s), %d thread(s), %d comment(s)\n",
+				verb, stats.ChannelsCreated, stats.ThreadsCreated, stats.CommentsCreated)
+			if stats.Skipped > 0 {
+				fmt.Printf("Skipped %d item(s) already imported\n", stats.Skipped)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("import failed: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormatFlag, "format", "", "Source format: 'backup' or 'slack' (detected from the path by default)")
+	importCmd.Flags().StringVar(&importUserMapFlag, "user-map", "", "JSON file mapping source users to Twist user IDs")
+	importCmd.Flags().StringVar(&importCheckpointFlag, "checkpoint", "twist-import-checkpoint.json", "Checkpoint file recording imported items")
+	importCmd.Flags().BoolVar(&importNoAttributionFlag, "no-attribution", false, "Do not prefix content with the original author and date")
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+// messageTarget describes where a message is posted, for resolving
+// mentions and --notify presets.
+type messageTarget struct {
+	WorkspaceID  int
+	ChannelID    int
+	Participants []int
+}
+
+// resolveMessage turns @name, @email and @group tokens in content into
+// Twist mention markup and resolves a --notify list of user IDs, names,
+// emails, group names and presets (see findRecipients). It returns the
+// rewritten content and everyone to notify. The target is only looked up
+// when names need resolving.
+func resolveMessage(client *api.Client, target func() (messageTarget, error), content, notify string) (string, []int, error) {
+	if !strings.Contains(content, "@") && numericList(notify) {
+		ids, err := (&directory{}).findUsers(notify)
+		return content, uniqueIDs(ids), err
+	}
+
+	t, err := target()
+	if err != nil {
+		return "", nil, err
+	}
+	// Without a known workspace, names are looked up in every workspace.
+	var dir *directory
+	if t.WorkspaceID != 0 {
+		dir, err = loadDirectory(client, t.WorkspaceID)
+	} else {
+		dir, err = loadDirectory(client)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	content, recipients, groupIDs, err := dir.resolveMentions(content)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, id := range groupIDs {
+		members, err := groupMembers(client, id)
+		if err != nil {
+			return "", nil, err
+		}
+		recipients = append(recipients, members...)
+	}
+
+	notified, err := findRecipients(client, dir, t, notify)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return content, uniqueIDs(append(recipients, notified...)), nil
+}
+
+// findRecipients resolves a comma-separated --notify list. Besides user IDs,
+// names, emails and group names it accepts the presets "everyone" (all
+// active workspace members), "channel" (members of the target channel) and
+// "participants" (participants of the thread being replied to).
+func findRecipients(client *api.Client, dir *directory, t messageTarget, refs string) ([]int, error) {
+	var ids []int
+	for _, ref := range strings.Split(refs, ",") {
+		ref = strings.TrimPrefix(strings.TrimSpace(ref), "@")
+		if ref == "" {
+			continue
+		}
+
+		if id, err := strconv.Atoi(ref); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+
+		switch strings.ToLower(ref) {
+		case "everyone":
+			for _, u := range dir.users {
+				if !u.Removed && !u.Bot {
+					ids = append(ids, u.ID)
+				}
+			}
+			continue
+		case "channel":
+			if t.ChannelID == 0 {
+				return nil, fmt.Errorf("'channel' can only be notified when posting in a channel")
+			}
+			channel, err := client.GetChannel(t.ChannelID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get channel: %w", err)
+			}
+			ids = append(ids, channel.UserIDs...)
+			continue
+		case "participants":
+			if t.Participants == nil {
+				return nil, fmt.Errorf("'participants' can only be notified when replying to a thread")
+			}
+			ids = append(ids, t.Participants...)
+			continue
+		}
+
+		if g, ok := dir.findGroup(ref); ok {
+			members, err := groupMembers(client, g.ID)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, members...)
+			continue
+		}
+
+		id, err := dir.findUser(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return uniqueIDs(ids), nil
+}
+
+func groupMembers(client *api.Client, groupID int) ([]int, error) {
+	group, err := client.GetGroup(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	return group.UserIDs, nil
+}
+
+// confirmRecipients asks before notifying more than threshold users. A
+// threshold of zero or less never asks.
+func confirmRecipients(recipients []int, threshold int) error {
+	if threshold <= 0 || len(recipients) <= threshold {
+		return nil
+	}
+	return confirmAction(fmt.Sprintf("This will notify %d users. Continue?", len(recipients)))
+}
+
+// resolveMentions rewrites mention tokens in content and returns the
+// mentioned users and groups. Tokens inside `code` and tokens that match
+// nobody are left untouched.
+func (d *directory) resolveMentions(content string) (string, []int, []int, error) {
+	var b strings.Builder
+	var userIDs, groupIDs []int
+	inCode := false
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		if c == '`' {
+			inCode = !inCode
+		}
+		if c != '@' || inCode || (i > 0 && !mentionBoundary(content[:i])) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		markup, userID, groupID, n, err := d.matchMention(content[i+1:])
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if n == 0 {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		b.WriteString(markup)
+		if groupID != 0 {
+			groupIDs = append(groupIDs, groupID)
+		} else {
+			userIDs = append(userIDs, userID)
+		}
+		i += 1 + n
+	}
+
+	return b.String(), userIDs, groupIDs, nil
+}
+
+// matchMention matches the text following an @ against group names, user
+// names and emails, preferring the longest match so that names containing
+// spaces work. Only exact matches count, so that words such as @here are
+// left alone; --notify is where names can be abbreviated. It returns the
+// markup, the matched user or group and the number of bytes consumed, or
+// zero bytes when nothing matches.
+func (d *directory) matchMention(rest string) (string, int, int, int, error) {
+	best := 0
+	var users []api.User
+	var group *api.Group
+
+	consider := func(candidate string) bool {
+		n := len(candidate)
+		if n == 0 || n < best || n > len(rest) || !strings.EqualFold(rest[:n], candidate) || !mentionEnd(rest[n:]) {
+			return false
+		}
+		if n > best {
+			best, users, group = n, nil, nil
+		}
+		return true
+	}
+
+	for _, g := range d.groups {
+		if consider(g.Name) {
+			g := g
+			group = &g
+		}
+	}
+	for _, u := range d.users {
+		if u.Removed {
+			continue
+		}
+		if consider(u.Name) || consider(u.Email) {
+			users = append(users, u)
+		}
+	}
+
+	if best == 0 {
+		return "", 0, 0, 0, nil
+	}
+
+	switch {
+	case len(users) > 1 || (len(users) == 1 && group != nil):
+		return "", 0, 0, 0, fmt.Errorf("@%s matches several users or groups", rest[:best])
+	case group != nil:
+		return fmt.Sprintf("[%s](twist-group-mention://%d)", group.Name, group.ID), 0, group.ID, best, nil
+	}
+	u := users[0]
+	return fmt.Sprintf("[%s](twist-mention://%d)", u.Name, u.ID), u.ID, 0, best, nil
+}
+
+func (d *directory) findGroup(name string) (api.Group, bool) {
+	for _, g := range d.groups {
+		if strings.EqualFold(g.Name, name) {
+			return g, true
+		}
+	}
+	return api.Group{}, false
+}
+
+// mentionBoundary reports whether an @ following before starts a mention,
+// so that addresses such as jane@example.com are left alone.
+func mentionBoundary(before string) bool {
+	r, _ := utf8.DecodeLastRuneInString(before)
+	return unicode.IsSpace(r) || strings.ContainsRune("([{\"'", r)
+}
+
+// mentionEnd reports whether a name may end where rest begins.
+func mentionEnd(rest string) bool {
+	if rest == "" {
+		return true
+	}
+	r, size := utf8.DecodeRuneInString(rest)
+	if r == '.' || r == '-' {
+		next, _ := utf8.DecodeRuneInString(rest[size:])
+		return !isNameRune(next)
+	}
+	return !isNameRune(r) && r != '@'
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func numericList(refs string) bool {
+	for _, ref := range strings.Split(refs, ",") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(ref); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	var unique []int
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Ints(unique)
+	return unique
+}
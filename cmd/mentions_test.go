@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+func testDirectory() *directory {
+	d := &directory{
+		users:  make(map[int]api.User),
+		groups: make(map[int]api.Group),
+	}
+	for _, u := range []api.User{
+		{ID: 1, Name: "Jane Doe", Email: "jane@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane.smith@example.com"},
+		{ID: 3, Name: "Herbert", Email: "herbert@example.com"},
+		{ID: 4, Name: "Bob", Email: "bob@example.com"},
+		{ID: 5, Name: "Robert", Email: "bob@other.example.com"},
+		{ID: 6, Name: "Old Timer", Email: "old@example.com", Removed: true},
+		{ID: 7, Name: "Ops", Email: "ops-person@example.com"},
+	} {
+		d.users[u.ID] = u
+	}
+	for _, g := range []api.Group{
+		{ID: 100, Name: "Design"},
+		{ID: 101, Name: "Ops"},
+	} {
+		d.groups[g.ID] = g
+	}
+	return d
+}
+
+func TestResolveMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		users   []int
+		groups  []int
+	}{
+		{
+			name:    "plain name",
+			content: "thanks @Herbert!",
+			want:    "thanks [Herbert](twist-mention://3)!",
+			users:   []int{3},
+		},
+		{
+			name:    "case is ignored",
+			content: "@herbert",
+			want:    "[Herbert](twist-mention://3)",
+			users:   []int{3},
+		},
+		{
+			name:    "longest name wins",
+			content: "ping @Jane Doe about it",
+			want:    "ping [Jane Doe](twist-mention://1) about it",
+			users:   []int{1},
+		},
+		{
+			name:    "shorter name when the longer one does not follow",
+			content: "ping @Jane Dough",
+			want:    "ping [Jane](twist-mention://2) Dough",
+			users:   []int{2},
+		},
+		{
+			name:    "email",
+			content: "cc @jane.smith@example.com.",
+			want:    "cc [Jane](twist-mention://2).",
+			users:   []int{2},
+		},
+		{
+			name:    "group",
+			content: "(@Design) please review",
+			want:    "([Design](twist-group-mention://100)) please review",
+			groups:  []int{100},
+		},
+		{
+			name:    "prefixes are not mentions",
+			content: "@here and @Herb and @Jan",
+			want:    "@here and @Herb and @Jan",
+		},
+		{
+			name:    "unknown name",
+			content: "hello @nobody",
+			want:    "hello @nobody",
+		},
+		{
+			name:    "name followed by letters",
+			content: "@Bobby",
+			want:    "@Bobby",
+		},
+		{
+			name:    "removed users",
+			content: "@Old Timer",
+			want:    "@Old Timer",
+		},
+		{
+			name:    "email addresses in text",
+			content: "write to bob@example.com",
+			want:    "write to bob@example.com",
+		},
+		{
+			name:    "inside code",
+			content: "run `@Bob deploy` then tell @Bob",
+			want:    "run `@Bob deploy` then tell [Bob](twist-mention://4)",
+			users:   []int{4},
+		},
+		{
+			name:    "several mentions",
+			content: "@Bob, @Robert and @Bob again",
+			want:    "[Bob](twist-mention://4), [Robert](twist-mention://5) and [Bob](twist-mention://4) again",
+			users:   []int{4, 5, 4},
+		},
+		{
+			name:    "non-ASCII after a name",
+			content: "@Bob’s idea",
+			want:    "[Bob](twist-mention://4)’s idea",
+			users:   []int{4},
+		},
+	}
+
+	d := testDirectory()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, users, groups, err := d.resolveMentions(tt.content)
+			if err != nil {
+				t.Fatalf("resolveMentions(%q): %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveMentions(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+			if !reflect.DeepEqual(users, tt.users) {
+				t.Errorf("resolveMentions(%q) users = %v, want %v", tt.content, users, tt.users)
+			}
+			if !reflect.DeepEqual(groups, tt.groups) {
+				t.Errorf("resolveMentions(%q) groups = %v, want %v", tt.content, groups, tt.groups)
+			}
+		})
+	}
+}
+
+func TestResolveMentionsAmbiguous(t *testing.T) {
+	d := testDirectory()
+	d.users[8] = api.User{ID: 8, Name: "Bob", Email: "bob2@example.com"}
+
+	for _, content := range []string{"@Bob", "@Ops"} {
+		_, _, _, err := d.resolveMentions(content)
+		if err == nil || !strings.Contains(err.Error(), "matches several") {
+			t.Errorf("resolveMentions(%q) error = %v, want an ambiguity error", content, err)
+		}
+	}
+}
+
+func TestFindUser(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr string
+	}{
+		{ref: "42", want: 42},
+		{ref: "@Herbert", want: 3},
+		{ref: "HERBERT@example.com", want: 3},
+		{ref: "jane", want: 2},
+		// --notify still accepts a unique prefix.
+		{ref: "Herb", want: 3},
+		{ref: "rob", want: 5},
+		{ref: "Jan", wantErr: "matches several users"},
+		{ref: "Old", wantErr: "no user"},
+		{ref: "nobody", wantErr: "no user"},
+	}
+
+	d := testDirectory()
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := d.findUser(tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("findUser(%q) = %d, %v; want error containing %q", tt.ref, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findUser(%q): %v", tt.ref, err)
+			}
+			if got != tt.want {
+				t.Errorf("findUser(%q) = %d, want %d", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
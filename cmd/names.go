@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+var errNoUser = errors.New("no user matches")
+
+// directory resolves user and channel IDs to names and back for commands
+// that accept or display names instead of raw IDs.
+type directory struct {
+	users    map[int]api.User
+	channels map[int]api.Channel
+	groups   map[int]api.Group
+}
+
+// loadDirectory fetches users, channels and groups of the given workspaces, or of
+// every workspace the token can see when none are given.
+func loadDirectory(client *api.Client, workspaceIDs ...int) (*directory, error) {
+	if len(workspaceIDs) == 0 {
+		workspaces, err := client.GetWorkspaces()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workspaces: %w", err)
+		}
+		for _, ws := range workspaces {
+			workspaceIDs = append(workspaceIDs, ws.ID)
+		}
+	}
+
+	d := &directory{
+		users:    make(map[int]api.User),
+		channels: make(map[int]api.Channel),
+		groups:   make(map[int]api.Group),
+	}
+	for _, wsID := range workspaceIDs {
+		users, err := client.GetWorkspaceUsers(wsID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
+		for _, u := range users {
+			d.users[u.ID] = u
+		}
+
+		for _, archived := range []bool{false, true} {
+			channels, err := client.GetChannels(wsID, archived)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get channels: %w", err)
+			}
+			for _, ch := range channels {
+				d.channels[ch.ID] = ch
+			}
+		}
+
+		groups, err := client.GetGroups(wsID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get groups: %w", err)
+		}
+		for _, g := range groups {
+			d.groups[g.ID] = g
+		}
+	}
+	return d, nil
+}
+
+func (d *directory) userName(id int) string {
+	if u, ok := d.users[id]; ok && u.Name != "" {
+		return u.Name
+	}
+	return fmt.Sprintf("User %d", id)
+}
+
+func (d *directory) channelName(id int) string {
+	if ch, ok := d.channels[id]; ok && ch.Name != "" {
+		return ch.Name
+	}
+	return fmt.Sprintf("channel %d", id)
+}
+
+// findUser resolves a user ID, email address or name. Names match exactly
+// (ignoring case) first and then by unique prefix.
+func (d *directory) findUser(ref string) (int, error) {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "@")
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+
+	lower := strings.ToLower(ref)
+	var prefix []api.User
+	for _, u := range d.users {
+		if u.Removed {
+			continue
+		}
+		if strings.EqualFold(u.Email, ref) || strings.EqualFold(u.Name, ref) {
+			return u.ID, nil
+		}
+		if strings.HasPrefix(strings.ToLower(u.Name), lower) {
+			prefix = append(prefix, u)
+		}
+	}
+
+	switch len(prefix) {
+	case 0:
+		return 0, fmt.Errorf("%w %q", errNoUser, ref)
+	case 1:
+		return prefix[0].ID, nil
+	}
+	names := make([]string, len(prefix))
+	for i, u := range prefix {
+		names[i] = u.Name
+	}
+	return 0, fmt.Errorf("%q matches several users: %s", ref, strings.Join(names, ", "))
+}
+
+// findChannel resolves a channel ID or name, with or without a leading #.
+func (d *directory) findChannel(ref string) (int, error) {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+
+	var matches []api.Channel
+	for _, ch := range d.channels {
+		if strings.EqualFold(ch.Name, ref) {
+			matches = append(matches, ch)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no channel named %q", ref)
+	case 1:
+		return matches[0].ID, nil
+	}
+	return 0, fmt.Errorf("%q matches %d channels; use the channel ID", ref, len(matches))
+}
+
+// findUsers resolves a comma-separated list of user references.
+func (d *directory) findUsers(refs string) ([]int, error) {
+	var ids []int
+	for _, ref := range strings.Split(refs, ",") {
+		if strings.TrimSpace(ref) == "" {
+			continue
+		}
+		id, err := d.findUser(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// resolveUserRefs resolves user IDs, names and emails, each argument possibly
+// a comma-separated list. Names are looked up in workspaceID, or in every
+// workspace when it is zero; plain IDs need no lookup.
+func resolveUserRefs(client *api.Client, workspaceID int, refs []string) ([]int, error) {
+	joined := strings.Join(refs, ",")
+	dir := &directory{}
+	if !numericList(joined) {
+		var err error
+		if workspaceID != 0 {
+			dir, err = loadDirectory(client, workspaceID)
+		} else {
+			dir, err = loadDirectory(client)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := dir.findUsers(joined)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no users given")
+	}
+	return uniqueIDs(ids), nil
+}
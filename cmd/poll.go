@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/emoji"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pollOptionFlags   []string
+	pollWorkspaceFlag int
+	pollMultipleFlag  bool
+	pollCloseFlag     bool
+)
+
+// pollHeader starts the content of every poll thread; the options follow
+// as "- <emoji> <label>" lines.
+const pollHeader = "**Poll:** vote by reacting with the emoji of your choice."
+
+// numberEmoji label options given without an emoji of their own.
+var numberEmoji = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+type pollOption struct {
+	Emoji string
+	Label string
+}
+
+var pollCmd = &cobra.Command{
+	Use:   "poll",
+	Short: "Run polls voted on with reactions",
+	Long:  `Create threads that ask a question and tally the reactions on them as votes.`,
+}
+
+var pollCreateCmd = &cobra.Command{
+	Use:   "create [channel] [question]",
+	Short: "Post a poll",
+	Long: `Post a thread asking a question, with one --option per answer. An option
+starts with the emoji (or :shortcode:) to vote with, e.g. --option "👍 yes";
+options without one are numbered 1️⃣, 2️⃣ and so on. The channel is an ID or,
+with --workspace, a name.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options, err := parsePollOptions(pollOptionFlags)
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		channelID, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			if pollWorkspaceFlag == 0 {
+				return fmt.Errorf("--workspace is required to look up channel %q by name", args[0])
+			}
+			dir, err := loadDirectory(client, pollWorkspaceFlag)
+			if err != nil {
+				return err
+			}
+			if channelID, err = dir.findChannel(args[0]); err != nil {
+				return err
+			}
+		}
+
+		var content strings.Builder
+		content.WriteString(pollHeader + "\n\n")
+		for _, o := range options {
+			fmt.Fprintf(&content, "- %s %s\n", o.Emoji, o.Label)
+		}
+
+		thread, err := client.CreateThread(channelID, args[1], content.String(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create poll: %w", err)
+		}
+
+		fmt.Printf("Poll created (thread #%d)\n", thread.ID)
+		for _, o := range options {
+			fmt.Printf("  %s %s\n", o.Emoji, o.Label)
+		}
+		return nil
+	},
+}
+
+var pollResultsCmd = &cobra.Command{
+	Use:   "results [thread-id]",
+	Short: "Tally the votes on a poll",
+	Long: `Count the reactions on a poll thread for each option, with the names of the
+voters. Each user gets one vote: anyone who reacted with several options is
+counted for their first choice only, unless --multiple is given. With --close
+the results are also posted to the thread as a closing comment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		thread, err := client.GetThread(threadID)
+		if err != nil {
+			return fmt.Errorf("failed to get thread: %w", err)
+		}
+		options, err := pollOptionsFromContent(thread.Content)
+		if err != nil {
+			return fmt.Errorf("thread %d: %w", threadID, err)
+		}
+
+		reactions, err := client.GetReactions("thread", threadID)
+		if err != nil {
+			return fmt.Errorf("failed to get reactions: %w", err)
+		}
+
+		dir, err := loadDirectory(client, thread.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		tally := tallyPoll(options, reactions, pollMultipleFlag)
+
+		fmt.Println(thread.Title)
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "OPTION\tVOTES\t%\tVOTERS")
+		fmt.Fprintln(w, "------\t-----\t-\t------")
+		for i, o := range options {
+			fmt.Fprintf(w, "%s %s\t%d\t%s\t%s\n", o.Emoji, o.Label, len(tally.votes[i]),
+				tally.percent(i), strings.Join(userNames(dir, tally.votes[i]), ", "))
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d voter(s)\n", tally.voters)
+		if len(tally.repeat) > 0 && !pollMultipleFlag {
+			fmt.Printf("Counted for their first choice only: %s\n", strings.Join(userNames(dir, tally.repeat), ", "))
+		}
+		if tally.ignored > 0 {
+			fmt.Printf("Ignored %d reaction(s) that match no option\n", tally.ignored)
+		}
+
+		if !pollCloseFlag {
+			return nil
+		}
+
+		var summary strings.Builder
+		summary.WriteString("**Poll closed.** Results:\n\n")
+		for i, o := range options {
+			fmt.Fprintf(&summary, "- %s %s: %d vote(s) (%s)", o.Emoji, o.Label, len(tally.votes[i]), tally.percent(i))
+			if names := userNames(dir, tally.votes[i]); len(names) > 0 {
+				fmt.Fprintf(&summary, " — %s", strings.Join(names, ", "))
+			}
+			summary.WriteString("\n")
+		}
+		fmt.Fprintf(&summary, "\n%d voter(s).", tally.voters)
+
+		comment, err := client.PostComment(threadID, summary.String(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to post results: %w", err)
+		}
+		fmt.Printf("Poll closed (comment #%d)\n", comment.ID)
+		return nil
+	},
+}
+
+// parsePollOptions splits each option into its emoji and label, numbering
+// the options that do not start with an emoji or :shortcode:.
+func parsePollOptions(raw []string) ([]pollOption, error) {
+	if len(raw) < 2 {
+		return nil, fmt.Errorf("a poll needs at least two --option values")
+	}
+
+	var options []pollOption
+	seen := make(map[string]bool)
+	numbered := 0
+	for _, r := range raw {
+		o := pollOption{Label: strings.TrimSpace(r)}
+		if first, rest, _ := strings.Cut(o.Label, " "); isEmojiToken(first) {
+			e, err := emoji.Resolve(first)
+			if err != nil {
+				return nil, fmt.Errorf("invalid option %q: %w", r, err)
+			}
+			o.Emoji, o.Label = e, strings.TrimSpace(rest)
+		} else {
+			if numbered >= len(numberEmoji) {
+				return nil, fmt.Errorf("option %q needs an emoji: only %d options can be numbered", r, len(numberEmoji))
+			}
+			o.Emoji = numberEmoji[numbered]
+			numbered++
+		}
+		if o.Label == "" {
+			return nil, fmt.Errorf("option %q has no text", r)
+		}
+
+		key := emoji.Normalize(o.Emoji)
+		if seen[key] {
+			return nil, fmt.Errorf("emoji %s is used by more than one option", o.Emoji)
+		}
+		seen[key] = true
+		options = append(options, o)
+	}
+	return options, nil
+}
+
+// isEmojiToken reports whether s looks like an emoji or a :shortcode: rather
+// than the first word of an option.
+func isEmojiToken(s string) bool {
+	if len(s) > 2 && strings.HasPrefix(s, ":") && strings.HasSuffix(s, ":") {
+		return true
+	}
+	// Keycaps such as 1️⃣ start with an ASCII digit, # or *.
+	if len(s) > 1 && strings.ContainsRune("0123456789#*", rune(s[0])) &&
+		strings.TrimPrefix(s[1:], "\uFE0F") == "\u20E3" {
+		return true
+	}
+	for _, r := range s {
+		switch {
+		case r >= 0x2000 && r <= 0x2BFF, // symbols, arrows, dingbats
+			r >= 0x1F000 && r <= 0x1FAFF, // emoji blocks
+			r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299,
+			r == 0xFE0F, r == 0x20E3, r >= 0xE0020 && r <= 0xE007F:
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// pollOptionsFromContent reads the options back from a poll thread.
+func pollOptionsFromContent(content string) ([]pollOption, error) {
+	if !strings.HasPrefix(content, pollHeader) {
+		return nil, fmt.Errorf("not a poll created with 'twist poll create'")
+	}
+
+	var options []pollOption
+	for _, line := range strings.Split(strings.TrimPrefix(content, pollHeader), "\n") {
+		line, ok := strings.CutPrefix(strings.TrimSpace(line), "- ")
+		if !ok {
+			continue
+		}
+		e, label, _ := strings.Cut(line, " ")
+		options = append(options, pollOption{Emoji: e, Label: label})
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("poll has no options")
+	}
+	return options, nil
+}
+
+type pollTally struct {
+	votes   [][]int // user IDs per option
+	voters  int
+	repeat  []int // users who reacted with more than one option
+	ignored int
+}
+
+func (t *pollTally) percent(i int) string {
+	if t.voters == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(len(t.votes[i]))*100/float64(t.voters))
+}
+
+// tallyPoll counts the reactions matching each option. Reactions are taken in
+// the order they were made, so without multiple a user's first choice wins.
+func tallyPoll(options []pollOption, reactions []api.Reaction, multiple bool) *pollTally {
+	index := make(map[string]int)
+	for i, o := range options {
+		index[emoji.Normalize(o.Emoji)] = i
+	}
+
+	sorted := append([]api.Reaction(nil), reactions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	t := &pollTally{votes: make([][]int, len(options))}
+	voted := make(map[int]int)
+	for _, r := range sorted {
+		i, ok := index[emoji.Normalize(r.Emoji)]
+		if !ok {
+			t.ignored++
+			continue
+		}
+		voted[r.UserID]++
+		switch voted[r.UserID] {
+		case 1:
+			t.voters++
+		case 2:
+			t.repeat = append(t.repeat, r.UserID)
+		}
+		if voted[r.UserID] == 1 || multiple {
+			t.votes[i] = append(t.votes[i], r.UserID)
+		}
+	}
+	return t
+}
+
+func userNames(dir *directory, ids []int) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = dir.userName(id)
+	}
+	return names
+}
+
+func init() {
+	pollCreateCmd.Flags().StringArrayVar(&pollOptionFlags, "option", nil, "Answer, optionally starting with its emoji (repeatable)")
+	pollCreateCmd.Flags().IntVar(&pollWorkspaceFlag, "workspace", 0, "Workspace to look up the channel name in")
+	pollResultsCmd.Flags().BoolVar(&pollMultipleFlag, "multiple", false, "Count every option a user reacted with")
+	pollResultsCmd.Flags().BoolVar(&pollCloseFlag, "close", false, "Post the results as a comment closing the poll")
+
+	pollCmd.AddCommand(pollCreateCmd)
+	pollCmd.AddCommand(pollResultsCmd)
+}
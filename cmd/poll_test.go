@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+func TestParsePollOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []string
+		want    []pollOption
+		wantErr string
+	}{
+		{
+			name: "numbered",
+			raw:  []string{"Tuesday", "Wednesday"},
+			want: []pollOption{{"1️⃣", "Tuesday"}, {"2️⃣", "Wednesday"}},
+		},
+		{
+			name: "emoji and shortcodes",
+			raw:  []string{"👍 yes", ":thumbsdown: no"},
+			want: []pollOption{{"👍", "yes"}, {"👎", "no"}},
+		},
+		{
+			// Options with an emoji of their own do not use up a number.
+			name: "mixed",
+			raw:  []string{"👍 yes", "maybe", "👎 no", "later"},
+			want: []pollOption{{"👍", "yes"}, {"1️⃣", "maybe"}, {"👎", "no"}, {"2️⃣", "later"}},
+		},
+		{
+			name: "keycap emoji",
+			raw:  []string{"2️⃣ second", "#️⃣ other"},
+			want: []pollOption{{"2️⃣", "second"}, {"#️⃣", "other"}},
+		},
+		{
+			name: "eleven options, one with an emoji",
+			raw:  []string{"🍕 pizza", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j"},
+			want: []pollOption{
+				{"🍕", "pizza"}, {"1️⃣", "a"}, {"2️⃣", "b"}, {"3️⃣", "c"}, {"4️⃣", "d"}, {"5️⃣", "e"},
+				{"6️⃣", "f"}, {"7️⃣", "g"}, {"8️⃣", "h"}, {"9️⃣", "i"}, {"🔟", "j"},
+			},
+		},
+		{
+			name:    "too many numbered options",
+			raw:     []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			wantErr: "only 10 options can be numbered",
+		},
+		{
+			name:    "one option",
+			raw:     []string{"yes"},
+			wantErr: "at least two",
+		},
+		{
+			name:    "emoji without text",
+			raw:     []string{"👍", "no"},
+			wantErr: "has no text",
+		},
+		{
+			name:    "repeated emoji",
+			raw:     []string{"👍 yes", ":+1: also yes"},
+			wantErr: "more than one option",
+		},
+		{
+			name:    "explicit number clashes with a numbered option",
+			raw:     []string{"1️⃣ first", "second"},
+			wantErr: "more than one option",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePollOptions(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parsePollOptions(%q) = %v, %v; want error containing %q", tt.raw, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePollOptions(%q): %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePollOptions(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollOptionsRoundTrip(t *testing.T) {
+	options := []pollOption{{"👍", "yes"}, {"1️⃣", "not sure yet"}}
+	content := pollHeader + "\n\n- 👍 yes\n- 1️⃣ not sure yet\n"
+	got, err := pollOptionsFromContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, options) {
+		t.Errorf("pollOptionsFromContent = %v, want %v", got, options)
+	}
+	if _, err := pollOptionsFromContent("Just a thread"); err == nil {
+		t.Error("pollOptionsFromContent accepted a thread that is not a poll")
+	}
+}
+
+func TestTallyPoll(t *testing.T) {
+	options := []pollOption{{"👍", "yes"}, {"👎", "no"}, {"1️⃣", "maybe"}}
+	// Listed out of order; IDs give the order the reactions were made in.
+	reactions := []api.Reaction{
+		{ID: 5, Emoji: "👍", UserID: 1},
+		{ID: 1, Emoji: "👎", UserID: 1},
+		{ID: 2, Emoji: "👍", UserID: 2},
+		{ID: 3, Emoji: "1⃣", UserID: 3}, // without the variation selector
+		{ID: 4, Emoji: "🎉", UserID: 4},
+	}
+
+	tests := []struct {
+		multiple bool
+		votes    [][]int
+		percent  []string
+	}{
+		{false, [][]int{{2}, {1}, {3}}, []string{"33%", "33%", "33%"}},
+		{true, [][]int{{2, 1}, {1}, {3}}, []string{"67%", "33%", "33%"}},
+	}
+	for _, tt := range tests {
+		tally := tallyPoll(options, reactions, tt.multiple)
+		if !reflect.DeepEqual(tally.votes, tt.votes) {
+			t.Errorf("multiple=%v: votes = %v, want %v", tt.multiple, tally.votes, tt.votes)
+		}
+		for i, want := range tt.percent {
+			if got := tally.percent(i); got != want {
+				t.Errorf("multiple=%v: percent(%d) = %s, want %s", tt.multiple, i, got, want)
+			}
+		}
+		if tally.voters != 3 || tally.ignored != 1 || !reflect.DeepEqual(tally.repeat, []int{1}) {
+			t.Errorf("multiple=%v: voters %d, ignored %d, repeat %v; want 3, 1, [1]", tt.multiple, tally.voters, tally.ignored, tally.repeat)
+		}
+	}
+
+	if empty := tallyPoll(options, nil, false); empty.percent(0) != "0%" {
+		t.Errorf("percent with no voters = %s, want 0%%", empty.percent(0))
+	}
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/emoji"
 	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
@@ -14,24 +17,19 @@ import (
 var reactionsCmd = &cobra.Command{
 	Use:   "reactions",
 	Short: "Manage reactions",
-	Long:  `Add, remove, and view reactions on threads and comments.`,
+	Long: `Add, remove, and view reactions on threads, comments and conversation
+messages. Emoji can be given directly or as :shortcodes: such as :+1: or :tada:.`,
 }
 
 var reactionsAddCmd = &cobra.Command{
 	Use:   "add [target-type] [target-id] [emoji]",
 	Short: "Add a reaction",
-	Long:  `Add an emoji reaction to a thread or comment. Target type must be 'thread' or 'comment'.`,
+	Long:  `Add an emoji reaction to a thread, comment or conversation message. Target type must be 'thread', 'comment' or 'message'.`,
 	Args:  cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		targetType := args[0]
-		targetID, err := strconv.Atoi(args[1])
+		targetType, targetID, e, err := parseReactionArgs(args)
 		if err != nil {
-			return fmt.Errorf("invalid target ID: %w", err)
-		}
-		emoji := args[2]
-
-		if targetType != "thread" && targetType != "comment" {
-			return fmt.Errorf("invalid target type: must be 'thread' or 'comment'")
+			return err
 		}
 
 		token, err := auth.GetToken(tokenFlag)
@@ -39,8 +37,8 @@ var reactionsAddCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
-		reaction, err := client.AddReaction(targetType, targetID, emoji)
+		client := newClient(token)
+		reaction, err := client.AddReaction(targetType, targetID, e)
 		if err != nil {
 			return fmt.Errorf("failed to add reaction: %w", err)
 		}
@@ -56,18 +54,12 @@ var reactionsAddCmd = &cobra.Command{
 var reactionsRemoveCmd = &cobra.Command{
 	Use:   "remove [target-type] [target-id] [emoji]",
 	Short: "Remove a reaction",
-	Long:  `Remove an emoji reaction from a thread or comment. Target type must be 'thread' or 'comment'.`,
+	Long:  `Remove an emoji reaction from a thread, comment or conversation message. Target type must be 'thread', 'comment' or 'message'.`,
 	Args:  cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		targetType := args[0]
-		targetID, err := strconv.Atoi(args[1])
+		targetType, targetID, e, err := parseReactionArgs(args)
 		if err != nil {
-			return fmt.Errorf("invalid target ID: %w", err)
-		}
-		emoji := args[2]
-
-		if targetType != "thread" && targetType != "comment" {
-			return fmt.Errorf("invalid target type: must be 'thread' or 'comment'")
+			return err
 		}
 
 		token, err := auth.GetToken(tokenFlag)
@@ -75,8 +67,8 @@ var reactionsRemoveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
-		if err := client.RemoveReaction(targetType, targetID, emoji); err != nil {
+		client := newClient(token)
+		if err := client.RemoveReaction(targetType, targetID, e); err != nil {
 			return fmt.Errorf("failed to remove reaction: %w", err)
 		}
 
@@ -86,20 +78,62 @@ var reactionsRemoveCmd = &cobra.Command{
 	},
 }
 
+var reactionsToggleCmd = &cobra.Command{
+	Use:   "toggle [target-type] [target-id] [emoji]",
+	Short: "Add a reaction, or remove it if already there",
+	Long:  `Add your emoji reaction to a thread, comment or conversation message, or remove it if you already reacted with that emoji. Target type must be 'thread', 'comment' or 'message'.`,
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetType, targetID, e, err := parseReactionArgs(args)
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		me, err := client.GetSessionUser()
+		if err != nil {
+			return fmt.Errorf("failed to get current user: %w", err)
+		}
+
+		reactions, err := client.GetReactions(targetType, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to get reactions: %w", err)
+		}
+
+		for _, r := range reactions {
+			if r.UserID == me.ID && emoji.Normalize(r.Emoji) == emoji.Normalize(e) {
+				if err := client.RemoveReaction(targetType, targetID, r.Emoji); err != nil {
+					return fmt.Errorf("failed to remove reaction: %w", err)
+				}
+				fmt.Printf("Removed %s\n", r.Emoji)
+				return nil
+			}
+		}
+
+		reaction, err := client.AddReaction(targetType, targetID, e)
+		if err != nil {
+			return fmt.Errorf("failed to add reaction: %w", err)
+		}
+		fmt.Printf("Added %s\n", reaction.Emoji)
+
+		return nil
+	},
+}
+
 var reactionsListCmd = &cobra.Command{
 	Use:   "list [target-type] [target-id]",
-	Short: "List all reactions",
-	Long:  `List all reactions on a thread or comment. Target type must be 'thread' or 'comment'.`,
+	Short: "Summarize reactions",
+	Long:  `Show each emoji reacted with on a thread, comment or conversation message, with how many people used it and who. Target type must be 'thread', 'comment' or 'message'.`,
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		targetType := args[0]
-		targetID, err := strconv.Atoi(args[1])
+		targetType, targetID, err := parseReactionTarget(args[0], args[1])
 		if err != nil {
-			return fmt.Errorf("invalid target ID: %w", err)
-		}
-
-		if targetType != "thread" && targetType != "comment" {
-			return fmt.Errorf("invalid target type: must be 'thread' or 'comment'")
+			return err
 		}
 
 		token, err := auth.GetToken(tokenFlag)
@@ -107,7 +141,7 @@ var reactionsListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		reactions, err := client.GetReactions(targetType, targetID)
 		if err != nil {
 			return fmt.Errorf("failed to get reactions: %w", err)
@@ -118,11 +152,42 @@ var reactionsListCmd = &cobra.Command{
 			return nil
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tEMOJI\tUSER ID")
-		fmt.Fprintln(w, "--\t-----\t-------")
+		workspaceID, err := reactionWorkspace(client, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		dir, err := loadDirectory(client, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		type summary struct {
+			emoji string
+			users []string
+		}
+		var summaries []*summary
+		byEmoji := make(map[string]*summary)
 		for _, r := range reactions {
-			fmt.Fprintf(w, "%d\t%s\t%d\n", r.ID, r.Emoji, r.UserID)
+			key := emoji.Normalize(r.Emoji)
+			s, ok := byEmoji[key]
+			if !ok {
+				s = &summary{emoji: r.Emoji}
+				byEmoji[key] = s
+				summaries = append(summaries, s)
+			}
+			s.users = append(s.users, dir.userName(r.UserID))
+		}
+		sort.SliceStable(summaries, func(i, j int) bool { return len(summaries[i].users) > len(summaries[j].users) })
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "EMOJI\tCOUNT\tUSERS")
+		fmt.Fprintln(w, "-----\t-----\t-----")
+		for _, s := range summaries {
+			label := s.emoji
+			if code := emoji.Shortcode(s.emoji); code != "" {
+				label += " " + code
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\n", label, len(s.users), strings.Join(s.users, ", "))
 		}
 		w.Flush()
 
@@ -130,8 +195,60 @@ var reactionsListCmd = &cobra.Command{
 	},
 }
 
+// reactionWorkspace returns the workspace of the thread, comment or message
+// reacted to.
+func reactionWorkspace(client *api.Client, targetType string, targetID int) (int, error) {
+	switch targetType {
+	case "thread":
+		thread, err := client.GetThread(targetID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get thread: %w", err)
+		}
+		return thread.WorkspaceID, nil
+	case "comment":
+		comment, err := client.GetComment(targetID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get comment: %w", err)
+		}
+		return comment.WorkspaceID, nil
+	default:
+		msg, err := client.GetConversationMessage(targetID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get message: %w", err)
+		}
+		return msg.WorkspaceID, nil
+	}
+}
+
+func parseReactionTarget(targetType, id string) (string, int, error) {
+	targetID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid target ID: %w", err)
+	}
+
+	if targetType != "thread" && targetType != "comment" && targetType != "message" {
+		return "", 0, fmt.Errorf("invalid target type: must be 'thread', 'comment' or 'message'")
+	}
+	return targetType, targetID, nil
+}
+
+// parseReactionArgs parses a target type, target ID and an emoji or
+// :shortcode:.
+func parseReactionArgs(args []string) (string, int, string, error) {
+	targetType, targetID, err := parseReactionTarget(args[0], args[1])
+	if err != nil {
+		return "", 0, "", err
+	}
+	e, err := emoji.Resolve(args[2])
+	if err != nil {
+		return "", 0, "", err
+	}
+	return targetType, targetID, e, nil
+}
+
 func init() {
 	reactionsCmd.AddCommand(reactionsAddCmd)
 	reactionsCmd.AddCommand(reactionsRemoveCmd)
+	reactionsCmd.AddCommand(reactionsToggleCmd)
 	reactionsCmd.AddCommand(reactionsListCmd)
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestReactionWorkspace(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		workspaces := map[string]int{
+			"/api/v3/threads/getone":               1,
+			"/api/v3/comments/getone":              2,
+			"/api/v3/conversation_messages/getone": 3,
+		}
+		id, ok := workspaces[r.URL.Path]
+		if !ok || r.URL.Query().Get("id") != "42" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]int{"id": 42, "workspace_id": id})
+	})
+
+	for targetType, want := range map[string]int{"thread": 1, "comment": 2, "message": 3} {
+		got, err := reactionWorkspace(client, targetType, 42)
+		if err != nil {
+			t.Fatalf("reactionWorkspace(%s): %v", targetType, err)
+		}
+		if got != want {
+			t.Errorf("reactionWorkspace(%s) = %d, want %d", targetType, got, want)
+		}
+	}
+}
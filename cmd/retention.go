@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/audit"
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/retention"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retentionConcurrencyFlag int
+)
+
+// retentionAction is a planned change to one thread.
+type retentionAction struct {
+	Rule   retention.Rule
+	Thread api.Thread
+}
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Apply retention policies to channels",
+	Long: `Archive or delete threads according to a YAML retention policy:
+
+  workspace: 12345
+  rules:
+    - name: stale alerts
+      channel: alerts
+      action: archive
+      where: last_updated < 30d
+    - name: old bot threads
+      channel: "*"
+      action: delete
+      where: bot && posted < 1y
+
+channel is a channel name or ID, or "*" for every channel. where takes the
+same expressions as 'twist threads bulk --where'. Rules are evaluated in
+order and the first rule matching a thread decides what happens to it.`,
+}
+
+var retentionPlanCmd = &cobra.Command{
+	Use:   "plan [policy.yaml]",
+	Short: "Show what a retention policy would change",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := retention.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		actions, dir, err := planRetention(client, policy)
+		if err != nil {
+			return err
+		}
+
+		if len(actions) == 0 {
+			fmt.Println("Nothing to do.")
+			return nil
+		}
+
+		printRetentionPlan(actions, dir)
+		return nil
+	},
+}
+
+var retentionApplyCmd = &cobra.Command{
+	Use:   "apply [policy.yaml]",
+	Short: "Apply a retention policy",
+	Long: `Archive or delete the threads selected by a retention policy. The plan is
+shown first and you are asked to confirm unless --yes is given; with
+--dry-run only the plan is shown. Every action is recorded in the audit log
+(see 'twist audit') as a RETENTION entry naming the rule and thread, next to
+the API request it made.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := retention.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if retentionConcurrencyFlag < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		actions, dir, err := planRetention(client, policy)
+		if err != nil {
+			return err
+		}
+
+		if len(actions) == 0 {
+			fmt.Println("Nothing to do.")
+			return nil
+		}
+
+		printRetentionPlan(actions, dir)
+		if dryRunFlag {
+			return nil
+		}
+		if err := confirmAction(fmt.Sprintf("Apply %d change(s)?", len(actions))); err != nil {
+			return err
+		}
+
+		auditPath, err := audit.DefaultPath()
+		if err != nil {
+			return err
+		}
+		profile := audit.Profile(token)
+
+		planned := make(map[int]retentionAction, len(actions))
+		threads := make([]api.Thread, len(actions))
+		for i, a := range actions {
+			planned[a.Thread.ID] = a
+			threads[i] = a.Thread
+		}
+
+		var auditMu sync.Mutex
+		var auditErr error
+		failures := runBulk(threads, retentionConcurrencyFlag, func(t api.Thread) error {
+			a := planned[t.ID]
+			var err error
+			if a.Rule.Action == "delete" {
+				err = client.DeleteThread(t.ID)
+			} else {
+				err = client.ArchiveThread(t.ID)
+			}
+
+			entry := audit.Entry{
+				Time:     time.Now(),
+				Profile:  profile,
+				Method:   "RETENTION",
+				Endpoint: retentionEndpoints[a.Rule.Action],
+				Result:   "ok",
+				Note:     fmt.Sprintf("rule %q: %s thread %d %q in channel %d", a.Rule.Name, a.Rule.Action, t.ID, t.Title, t.ChannelID),
+			}
+			if err != nil {
+				entry.Result = "error"
+				entry.Error = err.Error()
+			}
+
+			if werr := audit.Append(auditPath, entry); werr != nil {
+				auditMu.Lock()
+				if auditErr == nil {
+					auditErr = werr
+				}
+				auditMu.Unlock()
+			}
+			return err
+		})
+
+		fmt.Printf("%d succeeded, %d failed (audit log: %s)\n", len(actions)-len(failures), len(failures), auditPath)
+		for _, a := range actions {
+			if err, ok := failures[a.Thread.ID]; ok {
+				fmt.Fprintf(os.Stderr, "  thread %d: %v\n", a.Thread.ID, err)
+			}
+		}
+		if auditErr != nil {
+			return auditErr
+		}
+		if len(failures) > 0 {
+			return fmt.Errorf("%d of %d change(s) failed", len(failures), len(actions))
+		}
+		return nil
+	},
+}
+
+// retentionEndpoints names the API endpoint behind each action in the audit
+// log.
+var retentionEndpoints = map[string]string{
+	"archive": "/threads/archive",
+	"delete":  "/threads/remove",
+}
+
+// planRetention evaluates every rule of the policy against the threads of
+// its channels.
+func planRetention(client *api.Client, policy *retention.Policy) ([]retentionAction, *directory, error) {
+	for _, rule := range policy.Rules {
+		if err := rule.Filter.Check(threadFields()); err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid where: %w", rule.Name, err)
+		}
+	}
+
+	dir, err := loadDirectory(client, policy.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Resolve each rule's channels up front so that a typo fails the whole
+	// plan instead of silently matching nothing.
+	ruleChannels := make([]map[int]bool, len(policy.Rules))
+	var channelIDs []int
+	seen := make(map[int]bool)
+	for i, rule := range policy.Rules {
+		ruleChannels[i] = make(map[int]bool)
+		if rule.Channel == "*" {
+			for id, ch := range dir.channels {
+				if !ch.Archived && ch.WorkspaceID == policy.Workspace {
+					ruleChannels[i][id] = true
+				}
+			}
+		} else {
+			id, err := dir.findChannel(rule.Channel)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", rule.Name, err)
+			}
+			ruleChannels[i][id] = true
+		}
+		for id := range ruleChannels[i] {
+			if !seen[id] {
+				seen[id] = true
+				channelIDs = append(channelIDs, id)
+			}
+		}
+	}
+	sort.Ints(channelIDs)
+
+	var actions []retentionAction
+	for _, channelID := range channelIDs {
+		threads, err := allThreads(client, channelID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get threads for #%s: %w", dir.channelName(channelID), err)
+		}
+
+		for _, t := range threads {
+			record := threadRecord(t, dir)
+			for i, rule := range policy.Rules {
+				if !ruleChannels[i][channelID] {
+					continue
+				}
+				if rule.Action == "archive" && t.Archived {
+					continue
+				}
+				ok, err := rule.Filter.Match(record)
+				if err != nil {
+					return nil, nil, fmt.Errorf("%s: %w", rule.Name, err)
+				}
+				if ok {
+					actions = append(actions, retentionAction{Rule: rule, Thread: t})
+					break
+				}
+			}
+		}
+	}
+
+	return actions, dir, nil
+}
+
+func printRetentionPlan(actions []retentionAction, dir *directory) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tRULE\tCHANNEL\tID\tTITLE\tLAST UPDATED")
+	counts := make(map[string]int)
+	for _, a := range actions {
+		t := a.Thread
+		title := t.Title
+		if len(title) > 50 {
+			title = title[:47] + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t#%s\t%d\t%s\t%s\n", a.Rule.Action, a.Rule.Name, dir.channelName(t.ChannelID),
+			t.ID, title, time.Unix(t.LastUpdatedTS, 0).Format("2006-01-02"))
+		counts[a.Rule.Action]++
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d thread(s) to archive, %d to delete\n", counts["archive"], counts["delete"])
+}
+
+func init() {
+	retentionApplyCmd.Flags().IntVar(&retentionConcurrencyFlag, "concurrency", 4, "Number of threads to process at once")
+
+	retentionCmd.AddCommand(retentionPlanCmd)
+	retentionCmd.AddCommand(retentionApplyCmd)
+}
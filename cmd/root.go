@@ -8,7 +8,9 @@ import (
 )
 
 var (
-	tokenFlag string
+	tokenFlag  string
+	dryRunFlag bool
+	yesFlag    bool
 )
 
 var rootCmd = &cobra.Command{
@@ -29,6 +31,8 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&tokenFlag, "token", "", "Twist API token (or set TWIST_API_TOKEN env var)")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Print changes that would be sent to the API instead of sending them")
+	rootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Do not ask for confirmation")
 	rootCmd.AddCommand(workspacesCmd)
 	rootCmd.AddCommand(channelsCmd)
 	rootCmd.AddCommand(threadsCmd)
@@ -39,4 +43,11 @@ func init() {
 	rootCmd.AddCommand(attachmentsCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(usersCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(sqlCmd)
+	rootCmd.AddCommand(retentionCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(pollCmd)
 }
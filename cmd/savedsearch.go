@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/savedsearch"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchWatchIntervalFlag time.Duration
+	searchWatchOnceFlag     bool
+)
+
+var searchSaveCmd = &cobra.Command{
+	Use:   "save [name] [threads|messages|conversations|all] [workspace-id] [query]",
+	Short: "Save a search for later runs",
+	Long: `Save a search under a name. The search filter flags are saved with it.
+
+  twist search save outages all 123 "outage OR incident" --author jane
+  twist search run outages
+  twist search watch outages --interval 10m`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, kind := args[0], args[1]
+		workspaceID, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid workspace ID: %w", err)
+		}
+
+		switch kind {
+		case "threads", "messages", "conversations", "all":
+		default:
+			return fmt.Errorf("invalid search type: must be 'threads', 'messages', 'conversations' or 'all'")
+		}
+
+		store, err := savedsearch.Load()
+		if err != nil {
+			return err
+		}
+
+		s := searchFromFlags(kind, workspaceID, args[3])
+		s.Name = name
+		if kind != "all" {
+			s.In = ""
+		}
+		_, replaced := store.Searches[name]
+		store.Searches[name] = s
+		// Filters may have changed, so earlier hits say nothing about the new search.
+		delete(store.States, name)
+
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		if replaced {
+			fmt.Printf("Updated saved search '%s'\n", name)
+		} else {
+			fmt.Printf("Saved search '%s'\n", name)
+		}
+		return nil
+	},
+}
+
+var searchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedsearch.Load()
+		if err != nil {
+			return err
+		}
+
+		if len(store.Searches) == 0 {
+			fmt.Println("No saved searches.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tWORKSPACE\tQUERY\tLAST RUN")
+		for _, name := range store.Names() {
+			s := store.Searches[name]
+			lastRun := "never"
+			if st, ok := store.States[name]; ok && st.LastRunTS > 0 {
+				lastRun = time.Unix(st.LastRunTS, 0).Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", name, s.Kind, s.WorkspaceID, s.Query, lastRun)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var searchDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedsearch.Load()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := store.Searches[args[0]]; !ok {
+			return fmt.Errorf("no saved search named '%s'", args[0])
+		}
+		store.Delete(args[0])
+
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted saved search '%s'\n", args[0])
+		return nil
+	},
+}
+
+var searchRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "Run a saved search",
+	Long:  `Run a saved search and show all of its results. The results are recorded so that 'twist search watch' only reports hits that appear afterwards.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		s, dir, err := loadSavedSearch(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		hits, fresh, _, err := runSavedSearch(client, dir, s.Name)
+		if err != nil {
+			return err
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No results found matching the query.")
+			return nil
+		}
+
+		printSearchHits(hits, dir, s.Query)
+		fmt.Printf("Found %d result(s), %d new since the last run\n", len(hits), len(fresh))
+
+		return nil
+	},
+}
+
+var searchWatchCmd = &cobra.Command{
+	Use:   "watch [name]",
+	Short: "Re-run a saved search and report new hits",
+	Long: `Re-run a saved search every --interval and print only the hits that were
+not reported by an earlier run or watch. The first run of a search that has
+never been run only records the existing results.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchWatchIntervalFlag < time.Minute && !searchWatchOnceFlag {
+			return fmt.Errorf("--interval must be at least 1m")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		s, dir, err := loadSavedSearch(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		for {
+			hits, fresh, first, err := runSavedSearch(client, dir, s.Name)
+			now := time.Now().Format("2006-01-02 15:04")
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "%s: %v\n", now, err)
+			case first:
+				fmt.Printf("%s: recorded %d existing result(s) for '%s'; watching for new ones\n", now, len(hits), s.Name)
+			case len(fresh) > 0:
+				fmt.Printf("%s: %d new result(s) for '%s'\n\n", now, len(fresh), s.Name)
+				printSearchHits(fresh, dir, s.Query)
+			}
+
+			if searchWatchOnceFlag {
+				return err
+			}
+			time.Sleep(searchWatchIntervalFlag)
+		}
+	},
+}
+
+func loadSavedSearch(client *api.Client, name string) (savedsearch.Search, *directory, error) {
+	store, err := savedsearch.Load()
+	if err != nil {
+		return savedsearch.Search{}, nil, err
+	}
+
+	s, ok := store.Searches[name]
+	if !ok {
+		return savedsearch.Search{}, nil, fmt.Errorf("no saved search named '%s'", name)
+	}
+
+	dir, err := loadDirectory(client, s.WorkspaceID)
+	if err != nil {
+		return savedsearch.Search{}, nil, err
+	}
+	return s, dir, nil
+}
+
+// runSavedSearch runs the named search, records its hits as seen and returns
+// all hits together with those not seen before. first reports whether the
+// search had never been run.
+func runSavedSearch(client *api.Client, dir *directory, name string) (hits, fresh []searchHit, first bool, err error) {
+	// Reload on every run so that a long-running watch does not overwrite
+	// searches saved in the meantime.
+	store, err := savedsearch.Load()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	s, ok := store.Searches[name]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("no saved search named '%s'", name)
+	}
+
+	hits, err = runSearch(client, dir, s, 1)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	st := store.State(name)
+	first = st.LastRunTS == 0
+	for _, h := range hits {
+		key := h.Kind + ":" + strconv.Itoa(h.ID)
+		if !st.Seen[key] {
+			st.Seen[key] = true
+			fresh = append(fresh, h)
+		}
+	}
+	st.LastRunTS = time.Now().Unix()
+
+	if err := store.Save(); err != nil {
+		return nil, nil, false, err
+	}
+	return hits, fresh, first, nil
+}
+
+func init() {
+	searchSaveCmd.Flags().IntVar(&searchChannelIDFlag, "channel-id", 0, "Limit search to specific channel")
+	searchSaveCmd.Flags().StringVar(&searchInFlag, "in", "all", "Where to search with type 'all': 'all', 'threads' or 'conversations'")
+	searchSaveCmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	searchSaveCmd.Flags().StringVar(&searchAuthorFlag, "author", "", "Comma-separated author names, emails or IDs")
+	searchSaveCmd.Flags().StringVar(&searchSinceFlag, "since", "", "Only results on or after this date (YYYY-MM-DD)")
+	searchSaveCmd.Flags().StringVar(&searchUntilFlag, "until", "", "Only results on or before this date (YYYY-MM-DD)")
+	searchSaveCmd.Flags().BoolVar(&searchHasAttachmentsFlag, "has-attachments", false, "Only results with attachments")
+
+	searchWatchCmd.Flags().DurationVar(&searchWatchIntervalFlag, "interval", 5*time.Minute, "Time between runs")
+	searchWatchCmd.Flags().BoolVar(&searchWatchOnceFlag, "once", false, "Run once and exit (for cron)")
+
+	searchCmd.AddCommand(searchSaveCmd)
+	searchCmd.AddCommand(searchListCmd)
+	searchCmd.AddCommand(searchDeleteCmd)
+	searchCmd.AddCommand(searchRunCmd)
+	searchCmd.AddCommand(searchWatchCmd)
+}
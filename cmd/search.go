@@ -3,131 +3,557 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
-	"text/tabwriter"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/index"
+	"github.com/intelligrit/twist-cli/internal/savedsearch"
 	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
 var (
-	searchChannelIDFlag int
-	searchLimitFlag     int
+	searchChannelIDFlag      int
+	searchLimitFlag          int
+	searchLocalFlag          bool
+	searchReindexFlag        bool
+	searchAuthorFlag         string
+	searchSinceFlag          string
+	searchUntilFlag          string
+	searchHasAttachmentsFlag bool
+	searchPageFlag           int
+	searchInFlag             string
+	searchAllWorkspacesFlag  bool
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search",
 	Short: "Search content",
-	Long:  `Search threads, messages, and conversations.`,
-}
+	Long: `Search threads, messages, and conversations.
 
-var searchThreadsCmd = &cobra.Command{
-	Use:   "threads [workspace-id] [query]",
-	Short: "Search threads",
-	Long:  `Search for threads in a workspace. Use --channel-id to limit to a specific channel.`,
-	Args:  cobra.ExactArgs(2),
+With --local, search the offline index built by 'twist sync' instead of the
+server:
+
+  twist search --local 'deploy "release notes" -draft'
+  twist search --local 'outage OR incident channel:ops after:2024-01-01'
+  twist search --local 'author:jane (budget OR forecast) type:thread'
+
+Terms are combined with AND unless OR is given. NOT or a leading - excludes a
+term, double quotes match an exact phrase and parentheses group. Supported
+filters are author:, channel:, type: (thread, comment or message), before: and
+after: (YYYY-MM-DD).`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		workspaceID, err := strconv.Atoi(args[0])
+		if !searchLocalFlag {
+			return cmd.Help()
+		}
+
+		m, err := openMirror()
 		if err != nil {
-			return fmt.Errorf("invalid workspace ID: %w", err)
+			return err
 		}
-		query := args[1]
+		defer m.Close()
 
-		token, err := auth.GetToken(tokenFlag)
+		path, err := indexPath()
 		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+			return err
 		}
 
-		opts := make(map[string]interface{})
-		if searchChannelIDFlag > 0 {
-			opts["channel_id"] = searchChannelIDFlag
+		var idx *index.Index
+		if !searchReindexFlag {
+			idx, err = index.Load(path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if idx == nil {
+			idx, err = rebuildIndex(m)
+			if err != nil {
+				return fmt.Errorf("failed to build search index: %w", err)
+			}
+		}
+
+		if len(args) == 0 {
+			fmt.Printf("Search index: %d document(s)\n", len(idx.Docs))
+			return nil
 		}
-		if searchLimitFlag > 0 {
-			opts["limit"] = searchLimitFlag
+
+		hlOpen, hlClose := highlightMarkers()
+
+		limit := searchLimitFlag
+		if limit <= 0 {
+			limit = 20
 		}
 
-		client := api.NewClient(token)
-		threads, err := client.SearchThreads(workspaceID, query, opts)
+		results, err := idx.Search(strings.Join(args, " "), limit, hlOpen, hlClose)
 		if err != nil {
-			return fmt.Errorf("failed to search threads: %w", err)
+			return fmt.Errorf("invalid query: %w", err)
 		}
 
-		if len(threads) == 0 {
-			fmt.Println("No threads found matching the query.")
+		if len(results) == 0 {
+			fmt.Println("No results found matching the query.")
 			return nil
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tTITLE\tCHANNEL\tLAST UPDATED")
-		fmt.Fprintln(w, "--\t-----\t-------\t------------")
-		for _, t := range threads {
-			lastUpdated := time.Unix(t.LastUpdatedTS, 0).Format("2006-01-02 15:04")
-			title := t.Title
-			if title == "" {
-				title = "(no title)"
+		for _, r := range results {
+			d := r.Document
+			posted := time.Unix(d.PostedTS, 0).Format("2006-01-02 15:04")
+			author := d.Author
+			if author == "" {
+				author = fmt.Sprintf("User %d", d.AuthorID)
 			}
-			if len(title) > 40 {
-				title = title[:37] + "..."
+
+			switch d.Type {
+			case "thread":
+				fmt.Printf("[thread #%d] %s\n", d.ID, d.Title)
+			case "comment":
+				fmt.Printf("[comment #%d in thread #%d] %s\n", d.ID, d.ThreadID, d.ThreadTitle)
+			case "message":
+				fmt.Printf("[message #%d in conversation #%d]\n", d.ID, d.ConversationID)
+			}
+			where := author + " • " + posted
+			if d.Channel != "" {
+				where = "#" + d.Channel + " • " + where
 			}
-			fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", t.ID, title, t.ChannelID, lastUpdated)
+			fmt.Printf("  %s • score %.2f\n", where, r.Score)
+			fmt.Printf("  %s\n\n", r.Snippet)
 		}
-		w.Flush()
 
-		fmt.Printf("\nFound %d thread(s)\n", len(threads))
+		fmt.Printf("Found %d result(s)\n", len(results))
 
 		return nil
 	},
 }
 
-var searchMessagesCmd = &cobra.Command{
-	Use:   "messages [workspace-id] [query]",
-	Short: "Search messages",
-	Long:  `Search for messages/comments in a workspace.`,
-	Args:  cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		workspaceID, err := strconv.Atoi(args[0])
+// searchHit is a thread, comment or conversation message returned by one of
+// the server-side search endpoints, flattened for display.
+type searchHit struct {
+	Kind           string
+	ID             int
+	Title          string
+	Content        string
+	Creator        int
+	PostedTS       int64
+	WorkspaceID    int
+	Workspace      string
+	ChannelID      int
+	ThreadID       int
+	ConversationID int
+}
+
+func threadHits(workspaceID int, threads []api.Thread) []searchHit {
+	hits := make([]searchHit, 0, len(threads))
+	for _, t := range threads {
+		hits = append(hits, searchHit{
+			Kind:        "thread",
+			ID:          t.ID,
+			Title:       t.Title,
+			Content:     t.Content,
+			Creator:     t.Creator,
+			PostedTS:    t.PostedTS,
+			WorkspaceID: workspaceID,
+			ChannelID:   t.ChannelID,
+			ThreadID:    t.ID,
+		})
+	}
+	return hits
+}
+
+func commentHits(workspaceID int, comments []api.Comment) []searchHit {
+	hits := make([]searchHit, 0, len(comments))
+	for _, c := range comments {
+		hits = append(hits, searchHit{
+			Kind:        "comment",
+			ID:          c.ID,
+			Content:     c.Content,
+			Creator:     c.Creator,
+			PostedTS:    c.PostedTS,
+			WorkspaceID: workspaceID,
+			ChannelID:   c.ChannelID,
+			ThreadID:    c.ThreadID,
+		})
+	}
+	return hits
+}
+
+func messageHits(workspaceID int, messages []api.ConversationMessage) []searchHit {
+	hits := make([]searchHit, 0, len(messages))
+	for _, m := range messages {
+		wsID := workspaceID
+		if m.WorkspaceID != 0 {
+			wsID = m.WorkspaceID
+		}
+		hits = append(hits, searchHit{
+			Kind:           "message",
+			ID:             m.ID,
+			Content:        m.Content,
+			Creator:        m.UserID,
+			PostedTS:       m.CreatedTS,
+			WorkspaceID:    wsID,
+			ConversationID: m.ConversationID,
+		})
+	}
+	return hits
+}
+
+func (h searchHit) permalink() string {
+	if h.WorkspaceID == 0 {
+		return ""
+	}
+	switch h.Kind {
+	case "thread":
+		return api.ThreadURL(h.WorkspaceID, h.ChannelID, h.ThreadID)
+	case "comment":
+		if h.ChannelID == 0 {
+			return ""
+		}
+		return api.CommentURL(h.WorkspaceID, h.ChannelID, h.ThreadID, h.ID)
+	case "message":
+		return api.MessageURL(h.WorkspaceID, h.ConversationID, h.ID)
+	}
+	return ""
+}
+
+func printSearchHits(hits []searchHit, dir *directory, query string) {
+	hlOpen, hlClose := highlightMarkers()
+	terms := index.Tokenize(query)
+
+	for _, h := range hits {
+		switch h.Kind {
+		case "thread":
+			title := h.Title
+			if title == "" {
+				title = "(no title)"
+			}
+			fmt.Printf("[thread #%d] %s\n", h.ID, title)
+		case "comment":
+			fmt.Printf("[comment #%d in thread #%d]\n", h.ID, h.ThreadID)
+		case "message":
+			fmt.Printf("[message #%d in conversation #%d]\n", h.ID, h.ConversationID)
+		}
+
+		where := dir.userName(h.Creator) + " • " + time.Unix(h.PostedTS, 0).Format("2006-01-02 15:04")
+		if h.ChannelID != 0 {
+			where = "#" + dir.channelName(h.ChannelID) + " • " + where
+		}
+		if h.Workspace != "" {
+			where = h.Workspace + " / " + where
+		}
+		fmt.Printf("  %s\n", where)
+		if snippet := index.Snippet(h.Content, terms, hlOpen, hlClose); snippet != "" {
+			fmt.Printf("  %s\n", snippet)
+		}
+		if link := h.permalink(); link != "" {
+			fmt.Printf("  %s\n", link)
+		}
+		fmt.Println()
+	}
+}
+
+// highlightMarkers returns ANSI bold when writing to a terminal and
+// Markdown-style emphasis otherwise.
+func highlightMarkers() (string, string) {
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		return "\x1b[1;33m", "\x1b[0m"
+	}
+	return "**", "**"
+}
+
+// searchFromFlags captures the arguments and filter flags of a search
+// command so that it can be run directly or saved for later.
+func searchFromFlags(kind string, workspaceID int, query string) savedsearch.Search {
+	return savedsearch.Search{
+		Kind:           kind,
+		WorkspaceID:    workspaceID,
+		Query:          query,
+		ChannelID:      searchChannelIDFlag,
+		Author:         searchAuthorFlag,
+		Since:          searchSinceFlag,
+		Until:          searchUntilFlag,
+		HasAttachments: searchHasAttachmentsFlag,
+		In:             searchInFlag,
+		Limit:          searchLimitFlag,
+	}
+}
+
+// buildSearchOpts turns search filters into API options. Author names are
+// resolved through dir.
+func buildSearchOpts(dir *directory, s savedsearch.Search, page int) (map[string]interface{}, error) {
+	opts := make(map[string]interface{})
+	if s.Limit > 0 {
+		opts["limit"] = s.Limit
+	}
+	if page > 1 {
+		if s.Limit <= 0 {
+			return nil, fmt.Errorf("--page requires --limit")
+		}
+		opts["offset"] = (page - 1) * s.Limit
+	}
+	if s.ChannelID > 0 {
+		opts["channel_id"] = s.ChannelID
+	}
+	if s.Author != "" {
+		authorIDs, err := dir.findUsers(s.Author)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --author: %w", err)
+		}
+		opts["author_ids"] = authorIDs
+	}
+	if s.Since != "" {
+		since, err := time.ParseInLocation("2006-01-02", s.Since, time.Local)
 		if err != nil {
-			return fmt.Errorf("invalid workspace ID: %w", err)
+			return nil, fmt.Errorf("invalid --since date (use YYYY-MM-DD): %w", err)
 		}
-		query := args[1]
+		opts["date_from"] = since.Unix()
+	}
+	if s.Until != "" {
+		until, err := time.ParseInLocation("2006-01-02", s.Until, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --until date (use YYYY-MM-DD): %w", err)
+		}
+		opts["date_to"] = until.AddDate(0, 0, 1).Unix() - 1
+	}
+	if s.HasAttachments {
+		opts["has_attachments"] = true
+	}
+	return opts, nil
+}
 
-		token, err := auth.GetToken(tokenFlag)
+// runSearch runs a search of any kind and returns its hits, most recent
+// first when several endpoints are combined.
+func runSearch(client *api.Client, dir *directory, s savedsearch.Search, page int) ([]searchHit, error) {
+	opts, err := buildSearchOpts(dir, s, page)
+	if err != nil {
+		return nil, err
+	}
+
+	in := s.In
+	switch s.Kind {
+	case "threads", "messages", "conversations":
+		in = s.Kind
+	case "all":
+		if in == "" {
+			in = "all"
+		}
+		if in != "all" && in != "threads" && in != "conversations" {
+			return nil, fmt.Errorf("invalid --in: must be 'all', 'threads' or 'conversations'")
+		}
+	default:
+		return nil, fmt.Errorf("invalid search type %q: must be 'threads', 'messages', 'conversations' or 'all'", s.Kind)
+	}
+
+	var hits []searchHit
+	if in == "threads" || in == "all" {
+		threads, err := client.SearchThreads(s.WorkspaceID, s.Query, opts)
 		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+			return nil, fmt.Errorf("failed to search threads: %w", err)
+		}
+		hits = append(hits, threadHits(s.WorkspaceID, threads)...)
+	}
+	if in == "messages" || (s.Kind == "all" && in != "conversations") {
+		comments, err := client.SearchMessages(s.WorkspaceID, s.Query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search messages: %w", err)
+		}
+		hits = append(hits, commentHits(s.WorkspaceID, comments)...)
+	}
+	if in == "conversations" || (in == "all" && s.ChannelID == 0) {
+		messages, err := client.SearchConversations(s.Query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search conversations: %w", err)
 		}
+		hits = append(hits, messageHits(s.WorkspaceID, messages)...)
+	}
+
+	if s.Kind == "all" {
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].PostedTS > hits[j].PostedTS })
+	}
+	return hits, nil
+}
 
-		opts := make(map[string]interface{})
-		if searchLimitFlag > 0 {
-			opts["limit"] = searchLimitFlag
+func printSearchFooter(count int, noun string) {
+	fmt.Printf("Found %d %s(s)", count, noun)
+	if searchLimitFlag > 0 && count >= searchLimitFlag {
+		page := searchPageFlag
+		if page < 1 {
+			page = 1
 		}
+		fmt.Printf("; use --page %d for more", page+1)
+	}
+	fmt.Println()
+}
 
-		client := api.NewClient(token)
-		comments, err := client.SearchMessages(workspaceID, query, opts)
+// searchFromArgs parses "[workspace-id] [query]" and the filter flags and
+// runs the search. The workspace ID is omitted with --all-workspaces.
+func searchFromArgs(kind string, args []string) ([]searchHit, *directory, string, error) {
+	var workspaceID int
+	if searchAllWorkspacesFlag {
+		if len(args) != 1 {
+			return nil, nil, "", fmt.Errorf("expected only a query with --all-workspaces")
+		}
+		if searchChannelIDFlag > 0 {
+			return nil, nil, "", fmt.Errorf("--channel-id cannot be used with --all-workspaces")
+		}
+	} else {
+		if len(args) != 2 {
+			return nil, nil, "", fmt.Errorf("expected a workspace ID and a query (or --all-workspaces)")
+		}
+		id, err := strconv.Atoi(args[0])
 		if err != nil {
-			return fmt.Errorf("failed to search messages: %w", err)
+			return nil, nil, "", fmt.Errorf("invalid workspace ID: %w", err)
 		}
+		workspaceID = id
+	}
+	query := args[len(args)-1]
 
-		if len(comments) == 0 {
-			fmt.Println("No messages found matching the query.")
-			return nil
+	token, err := auth.GetToken(tokenFlag)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("authentication failed: %w", err)
+	}
+
+	client := newClient(token)
+	s := searchFromFlags(kind, workspaceID, query)
+
+	if searchAllWorkspacesFlag {
+		dir, err := loadDirectory(client)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		hits, err := searchAllWorkspaces(client, dir, s, searchPageFlag)
+		return hits, dir, query, err
+	}
+
+	dir, err := loadDirectory(client, workspaceID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	hits, err := runSearch(client, dir, s, searchPageFlag)
+	return hits, dir, query, err
+}
+
+// searchAllWorkspaces runs s in every workspace concurrently and merges the
+// hits, most recent first, labelled with their workspace. Conversation
+// search is not scoped to a workspace, so it runs only once.
+func searchAllWorkspaces(client *api.Client, dir *directory, s savedsearch.Search, page int) ([]searchHit, error) {
+	workspaces, err := client.GetWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	// Each workspace ranks its own hits, so page n of the merged results can
+	// only be cut from the first n pages of every workspace.
+	if page < 1 {
+		page = 1
+	}
+	if page > 1 && s.Limit == 0 {
+		return nil, fmt.Errorf("--page requires --limit")
+	}
+	first := s
+	if page > 1 {
+		first.Limit = page * s.Limit
+	}
+
+	var hits []searchHit
+	perWorkspace := first
+	if s.Kind == "all" && s.In != "threads" {
+		conversations := first
+		conversations.Kind = "conversations"
+		hits, err = runSearch(client, dir, conversations, 1)
+		if err != nil {
+			return nil, err
+		}
+		perWorkspace.In = "threads"
+	}
+
+	if s.Kind != "conversations" && !(s.Kind == "all" && s.In == "conversations") {
+		results := make([][]searchHit, len(workspaces))
+		errs := make([]error, len(workspaces))
+		var wg sync.WaitGroup
+		for i, ws := range workspaces {
+			wg.Add(1)
+			go func(i int, workspaceID int) {
+				defer wg.Done()
+				wsSearch := perWorkspace
+				wsSearch.WorkspaceID = workspaceID
+				results[i], errs[i] = runSearch(client, dir, wsSearch, 1)
+			}(i, ws.ID)
 		}
+		wg.Wait()
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tTHREAD\tCONTENT\tPOSTED")
-		fmt.Fprintln(w, "--\t------\t-------\t------")
-		for _, c := range comments {
-			posted := time.Unix(c.PostedTS, 0).Format("2006-01-02 15:04")
-			content := c.Content
-			if len(content) > 50 {
-				content = content[:47] + "..."
+		for i, ws := range workspaces {
+			if errs[i] != nil {
+				return nil, fmt.Errorf("workspace %s: %w", ws.Name, errs[i])
 			}
-			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", c.ID, c.ThreadID, content, posted)
+			hits = append(hits, results[i]...)
+		}
+	}
+
+	names := make(map[int]string, len(workspaces))
+	for _, ws := range workspaces {
+		names[ws.ID] = ws.Name
+	}
+	for i := range hits {
+		hits[i].Workspace = names[hits[i].WorkspaceID]
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].PostedTS > hits[j].PostedTS })
+	if s.Limit > 0 {
+		start := (page - 1) * s.Limit
+		if start > len(hits) {
+			start = len(hits)
+		}
+		hits = hits[start:min(start+s.Limit, len(hits))]
+	}
+	return hits, nil
+}
+
+var searchThreadsCmd = &cobra.Command{
+	Use:   "threads [workspace-id] [query]",
+	Short: "Search threads",
+	Long: `Search for threads in a workspace. Use --channel-id to limit to a specific channel.
+With --all-workspaces, omit the workspace ID to search every workspace.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hits, dir, query, err := searchFromArgs("threads", args)
+		if err != nil {
+			return err
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No threads found matching the query.")
+			return nil
+		}
+
+		printSearchHits(hits, dir, query)
+		printSearchFooter(len(hits), "thread")
+
+		return nil
+	},
+}
+
+var searchMessagesCmd = &cobra.Command{
+	Use:   "messages [workspace-id] [query]",
+	Short: "Search messages",
+	Long: `Search for messages/comments in a workspace.
+With --all-workspaces, omit the workspace ID to search every workspace.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hits, dir, query, err := searchFromArgs("messages", args)
+		if err != nil {
+			return err
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No messages found matching the query.")
+			return nil
 		}
-		w.Flush()
 
-		fmt.Printf("\nFound %d message(s)\n", len(comments))
+		printSearchHits(hits, dir, query)
+		printSearchFooter(len(hits), "message")
 
 		return nil
 	},
@@ -146,12 +572,17 @@ var searchConversationsCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		opts := make(map[string]interface{})
-		if searchLimitFlag > 0 {
-			opts["limit"] = searchLimitFlag
+		client := newClient(token)
+		dir, err := loadDirectory(client)
+		if err != nil {
+			return err
+		}
+
+		opts, err := buildSearchOpts(dir, searchFromFlags("conversations", 0, query), searchPageFlag)
+		if err != nil {
+			return err
 		}
 
-		client := api.NewClient(token)
 		messages, err := client.SearchConversations(query, opts)
 		if err != nil {
 			return fmt.Errorf("failed to search conversations: %w", err)
@@ -162,34 +593,70 @@ var searchConversationsCmd = &cobra.Command{
 			return nil
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tCONVERSATION\tCONTENT\tPOSTED")
-		fmt.Fprintln(w, "--\t------------\t-------\t------")
-		for _, m := range messages {
-			posted := time.Unix(m.CreatedTS, 0).Format("2006-01-02 15:04")
-			content := m.Content
-			if len(content) > 50 {
-				content = content[:47] + "..."
-			}
-			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", m.ID, m.ConversationID, content, posted)
+		printSearchHits(messageHits(0, messages), dir, query)
+		printSearchFooter(len(messages), "message")
+
+		return nil
+	},
+}
+
+var searchAllCmd = &cobra.Command{
+	Use:   "all [workspace-id] [query]",
+	Short: "Search threads, comments and conversations at once",
+	Long: `Search threads, comments and direct messages in a workspace and show the
+combined results, most recent first. Use --in to restrict the search to
+threads (threads and their comments) or conversations. With --all-workspaces,
+omit the workspace ID to search every workspace.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hits, dir, query, err := searchFromArgs("all", args)
+		if err != nil {
+			return err
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No results found matching the query.")
+			return nil
 		}
-		w.Flush()
 
-		fmt.Printf("\nFound %d message(s)\n", len(messages))
+		printSearchHits(hits, dir, query)
+		fmt.Printf("Found %d result(s)\n", len(hits))
 
 		return nil
 	},
 }
 
+func addSearchFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	cmd.Flags().IntVar(&searchPageFlag, "page", 1, "Page of results to show (with --limit)")
+	cmd.Flags().StringVar(&searchAuthorFlag, "author", "", "Comma-separated author names, emails or IDs")
+	cmd.Flags().StringVar(&searchSinceFlag, "since", "", "Only results on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&searchUntilFlag, "until", "", "Only results on or before this date (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&searchHasAttachmentsFlag, "has-attachments", false, "Only results with attachments")
+}
+
 func init() {
+	searchCmd.Flags().BoolVar(&searchLocalFlag, "local", false, "Search the offline index instead of the server")
+	searchCmd.Flags().BoolVar(&searchReindexFlag, "reindex", false, "Rebuild the offline index from the mirror first")
+	searchCmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	searchCmd.Flags().StringVar(&mirrorPathFlag, "db", "", "Path to the mirror database")
+
 	searchThreadsCmd.Flags().IntVar(&searchChannelIDFlag, "channel-id", 0, "Limit search to specific channel")
-	searchThreadsCmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	searchThreadsCmd.Flags().BoolVar(&searchAllWorkspacesFlag, "all-workspaces", false, "Search every workspace")
+	addSearchFilterFlags(searchThreadsCmd)
+
+	searchMessagesCmd.Flags().BoolVar(&searchAllWorkspacesFlag, "all-workspaces", false, "Search every workspace")
+	addSearchFilterFlags(searchMessagesCmd)
 
-	searchMessagesCmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	addSearchFilterFlags(searchConversationsCmd)
 
-	searchConversationsCmd.Flags().IntVar(&searchLimitFlag, "limit", 0, "Maximum number of results")
+	searchAllCmd.Flags().IntVar(&searchChannelIDFlag, "channel-id", 0, "Limit search to specific channel")
+	searchAllCmd.Flags().StringVar(&searchInFlag, "in", "all", "Where to search: 'all', 'threads' or 'conversations'")
+	searchAllCmd.Flags().BoolVar(&searchAllWorkspacesFlag, "all-workspaces", false, "Search every workspace")
+	addSearchFilterFlags(searchAllCmd)
 
 	searchCmd.AddCommand(searchThreadsCmd)
 	searchCmd.AddCommand(searchMessagesCmd)
 	searchCmd.AddCommand(searchConversationsCmd)
+	searchCmd.AddCommand(searchAllCmd)
 }
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/index"
+	"github.com/intelligrit/twist-cli/internal/mirror"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mirrorPathFlag string
+	syncQuietFlag  bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [workspace-id]",
+	Short: "Mirror a workspace into a local SQLite database",
+	Long: `Mirror a workspace into a local SQLite database for offline reading and querying.
+
+Only threads and conversations updated since the previous sync are re-fetched.
+The mirror is stored in the config directory unless --db is given. Use
+'twist sql' to query it and --offline on 'threads list' and 'threads show'
+to read from it. The local search index used by 'twist search --local' is
+rebuilt after every sync.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid workspace ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		m, err := openMirror()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+
+		var log io.Writer
+		if !syncQuietFlag {
+			log = os.Stderr
+		}
+
+		client := newClient(token)
+		stats, err := m.Sync(client, workspaceID, log)
+		if err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+
+		fmt.Printf("Channels: %d\n", stats.Channels)
+		fmt.Printf("Threads: %d updated, %d unchanged, %d removed (%d comments fetched)\n",
+			stats.ThreadsUpdated, stats.ThreadsUnchanged, stats.ThreadsRemoved, stats.Comments)
+		fmt.Printf("Conversations: %d updated, %d unchanged (%d messages fetched)\n",
+			stats.ConversationsUpdated, stats.ConversationsUnchanged, stats.Messages)
+
+		idx, err := rebuildIndex(m)
+		if err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+		fmt.Printf("Search index: %d document(s)\n", len(idx.Docs))
+
+		return nil
+	},
+}
+
+var sqlCmd = &cobra.Command{
+	Use:   "sql [query...]",
+	Short: "Query the local mirror with SQL",
+	Long: `Run a read-only SQL query against the local mirror created by 'twist sync'.
+
+Tables: workspaces, users, channels, threads, comments, conversations,
+messages and sync_state.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.Join(args, " ")
+
+		path, err := mirrorPath()
+		if err != nil {
+			return err
+		}
+		m, err := mirror.OpenReadOnly(path)
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+
+		columns, rows, err := m.Query(query)
+		if err != nil {
+			return fmt.Errorf("query failed: %w", err)
+		}
+
+		if len(columns) == 0 {
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		underline := make([]string, len(columns))
+		for i, c := range columns {
+			underline[i] = strings.Repeat("-", len(c))
+		}
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+		fmt.Fprintln(w, strings.Join(underline, "\t"))
+		for _, row := range rows {
+			for i, v := range row {
+				row[i] = strings.ReplaceAll(v, "\n", " ")
+			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d row(s)\n", len(rows))
+
+		return nil
+	},
+}
+
+func mirrorPath() (string, error) {
+	if mirrorPathFlag != "" {
+		return mirrorPathFlag, nil
+	}
+	return mirror.DefaultPath()
+}
+
+func openMirror() (*mirror.Mirror, error) {
+	path, err := mirrorPath()
+	if err != nil {
+		return nil, err
+	}
+	return mirror.Open(path)
+}
+
+// The search index lives next to the mirror it was built from.
+func indexPath() (string, error) {
+	path, err := mirrorPath()
+	if err != nil {
+		return "", err
+	}
+	return path + ".idx", nil
+}
+
+func rebuildIndex(m *mirror.Mirror) (*index.Index, error) {
+	docs, err := m.Documents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+
+	path, err := indexPath()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := index.Build(docs)
+	if err := idx.Save(path); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&mirrorPathFlag, "db", "", "Path to the mirror database")
+	syncCmd.Flags().BoolVarP(&syncQuietFlag, "quiet", "q", false, "Do not print progress")
+
+	sqlCmd.Flags().StringVar(&mirrorPathFlag, "db", "", "Path to the mirror database")
+}
@@ -18,6 +18,10 @@ var (
 	replyNotifyFlag  string
 	titleFlag        string
 	contentFlag      string
+	offlineFlag      bool
+
+	notifyConfirmOverFlag int
+	mergeKeepSourceFlag   bool
 )
 
 var threadsCmd = &cobra.Command{
@@ -29,7 +33,7 @@ var threadsCmd = &cobra.Command{
 var threadsListCmd = &cobra.Command{
 	Use:   "list [channel-id]",
 	Short: "List all threads in a channel",
-	Long:  `List all threads in a specific channel by providing the channel ID.`,
+	Long:  `List all threads in a specific channel by providing the channel ID. Use --offline to read from the local mirror.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		channelID, err := strconv.Atoi(args[0])
@@ -37,15 +41,29 @@ var threadsListCmd = &cobra.Command{
 			return fmt.Errorf("invalid channel ID: %w", err)
 		}
 
-		token, err := auth.GetToken(tokenFlag)
-		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
-		}
+		var threads []api.Thread
+		if offlineFlag {
+			m, err := openMirror()
+			if err != nil {
+				return err
+			}
+			defer m.Close()
 
-		client := api.NewClient(token)
-		threads, err := client.GetThreads(channelID)
-		if err != nil {
-			return fmt.Errorf("failed to get threads: %w", err)
+			threads, err = m.Threads(channelID)
+			if err != nil {
+				return fmt.Errorf("failed to read threads from mirror: %w", err)
+			}
+		} else {
+			token, err := auth.GetToken(tokenFlag)
+			if err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+
+			client := newClient(token)
+			threads, err = client.GetThreads(channelID)
+			if err != nil {
+				return fmt.Errorf("failed to get threads: %w", err)
+			}
 		}
 
 		if len(threads) == 0 {
@@ -76,7 +94,7 @@ var threadsListCmd = &cobra.Command{
 var threadsShowCmd = &cobra.Command{
 	Use:   "show [thread-id]",
 	Short: "Show a thread with its content and replies",
-	Long:  `Display the full content of a thread including all comments/replies.`,
+	Long:  `Display the full content of a thread including all comments/replies. Use --offline to read from the local mirror.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID, err := strconv.Atoi(args[0])
@@ -84,21 +102,41 @@ var threadsShowCmd = &cobra.Command{
 			return fmt.Errorf("invalid thread ID: %w", err)
 		}
 
-		token, err := auth.GetToken(tokenFlag)
-		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
-		}
+		var thread *api.Thread
+		var comments []api.Comment
+		if offlineFlag {
+			m, err := openMirror()
+			if err != nil {
+				return err
+			}
+			defer m.Close()
+
+			thread, err = m.Thread(threadID)
+			if err != nil {
+				return fmt.Errorf("failed to read thread from mirror: %w", err)
+			}
 
-		client := api.NewClient(token)
+			comments, err = m.Comments(threadID)
+			if err != nil {
+				return fmt.Errorf("failed to read comments from mirror: %w", err)
+			}
+		} else {
+			token, err := auth.GetToken(tokenFlag)
+			if err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
 
-		thread, err := client.GetThread(threadID)
-		if err != nil {
-			return fmt.Errorf("failed to get thread: %w", err)
-		}
+			client := newClient(token)
 
-		comments, err := client.GetComments(threadID)
-		if err != nil {
-			return fmt.Errorf("failed to get comments: %w", err)
+			thread, err = client.GetThread(threadID)
+			if err != nil {
+				return fmt.Errorf("failed to get thread: %w", err)
+			}
+
+			comments, err = client.GetComments(threadID)
+			if err != nil {
+				return fmt.Errorf("failed to get comments: %w", err)
+			}
 		}
 
 		fmt.Println("================================================================================")
@@ -128,8 +166,13 @@ var threadsShowCmd = &cobra.Command{
 var threadsReplyCmd = &cobra.Command{
 	Use:   "reply [thread-id] [message]",
 	Short: "Reply to a thread",
-	Long:  `Post a comment/reply to an existing thread. Use --notify to specify user IDs to notify (comma-separated).`,
-	Args:  cobra.MinimumNArgs(2),
+	Long: `Post a comment/reply to an existing thread. Use --notify to specify users to
+notify (comma-separated IDs, names, emails or group names) or the preset
+'everyone', 'channel' or 'participants'. @name, @email and @group mentions in
+the content become Twist mentions and are notified too. You are asked to
+confirm before more than --confirm-over users are notified. Use --attach to
+include files with the reply.`,
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -138,34 +181,53 @@ var threadsReplyCmd = &cobra.Command{
 
 		content := strings.Join(args[1:], " ")
 
+		attachPaths, err := checkAttachPaths(attachFlag)
+		if err != nil {
+			return err
+		}
+
 		token, err := auth.GetToken(tokenFlag)
 		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		var recipients []int
-		if replyNotifyFlag != "" {
-			userIDs := strings.Split(replyNotifyFlag, ",")
-			for _, idStr := range userIDs {
-				idStr = strings.TrimSpace(idStr)
-				if idStr == "" {
-					continue
-				}
-				id, err := strconv.Atoi(idStr)
-				if err != nil {
-					return fmt.Errorf("invalid user ID: %s", idStr)
-				}
-				recipients = append(recipients, id)
+		client := newClient(token)
+		content, recipients, err := resolveMessage(client, func() (messageTarget, error) {
+			thread, err := client.GetThread(threadID)
+			if err != nil {
+				return messageTarget{}, fmt.Errorf("failed to get thread: %w", err)
+			}
+			participants := thread.Participants
+			if participants == nil {
+				participants = []int{}
 			}
+			return messageTarget{
+				WorkspaceID:  thread.WorkspaceID,
+				ChannelID:    thread.ChannelID,
+				Participants: participants,
+			}, nil
+		}, content, replyNotifyFlag)
+		if err != nil {
+			return err
+		}
+		if err := confirmRecipients(recipients, notifyConfirmOverFlag); err != nil {
+			return err
 		}
 
-		client := api.NewClient(token)
-		comment, err := client.PostComment(threadID, content, recipients)
+		attachments, err := uploadAttachments(client, attachPaths)
+		if err != nil {
+			return err
+		}
+
+		comment, err := client.PostComment(threadID, content, recipients, attachments...)
 		if err != nil {
 			return fmt.Errorf("failed to post reply: %w", err)
 		}
 
 		fmt.Printf("Reply posted successfully (comment #%d)\n", comment.ID)
+		if len(attachments) > 0 {
+			fmt.Printf("Attached %d file(s)\n", len(attachments))
+		}
 		if len(recipients) > 0 {
 			fmt.Printf("Notified %d user(s)\n", len(recipients))
 		}
@@ -176,8 +238,13 @@ var threadsReplyCmd = &cobra.Command{
 var threadsCreateCmd = &cobra.Command{
 	Use:   "create [channel-id] [title] [content]",
 	Short: "Create a new thread",
-	Long:  `Create a new thread in a channel. Use --notify to specify user IDs to notify (comma-separated).`,
-	Args:  cobra.MinimumNArgs(3),
+	Long: `Create a new thread in a channel. Use --notify to specify users to notify
+(comma-separated IDs, names, emails or group names) or the preset 'everyone' or
+'channel'. @name, @email and @group mentions in the content become Twist
+mentions and are notified too. You are asked to confirm before more than
+--confirm-over users are notified. Use --attach to include files with the
+thread.`,
+	Args: cobra.MinimumNArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		channelID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -187,29 +254,37 @@ var threadsCreateCmd = &cobra.Command{
 		title := args[1]
 		content := strings.Join(args[2:], " ")
 
+		attachPaths, err := checkAttachPaths(attachFlag)
+		if err != nil {
+			return err
+		}
+
 		token, err := auth.GetToken(tokenFlag)
 		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		var recipients []int
-		if createNotifyFlag != "" {
-			userIDs := strings.Split(createNotifyFlag, ",")
-			for _, idStr := range userIDs {
-				idStr = strings.TrimSpace(idStr)
-				if idStr == "" {
-					continue
-				}
-				id, err := strconv.Atoi(idStr)
-				if err != nil {
-					return fmt.Errorf("invalid user ID: %s", idStr)
-				}
-				recipients = append(recipients, id)
+		client := newClient(token)
+		content, recipients, err := resolveMessage(client, func() (messageTarget, error) {
+			channel, err := client.GetChannel(channelID)
+			if err != nil {
+				return messageTarget{}, fmt.Errorf("failed to get channel: %w", err)
 			}
+			return messageTarget{WorkspaceID: channel.WorkspaceID, ChannelID: channel.ID}, nil
+		}, content, createNotifyFlag)
+		if err != nil {
+			return err
+		}
+		if err := confirmRecipients(recipients, notifyConfirmOverFlag); err != nil {
+			return err
+		}
+
+		attachments, err := uploadAttachments(client, attachPaths)
+		if err != nil {
+			return err
 		}
 
-		client := api.NewClient(token)
-		thread, err := client.CreateThread(channelID, title, content, recipients)
+		thread, err := client.CreateThread(channelID, title, content, recipients, attachments...)
 		if err != nil {
 			return fmt.Errorf("failed to create thread: %w", err)
 		}
@@ -217,6 +292,9 @@ var threadsCreateCmd = &cobra.Command{
 		fmt.Printf("Thread created successfully!\n")
 		fmt.Printf("Thread ID: %d\n", thread.ID)
 		fmt.Printf("Title: %s\n", thread.Title)
+		if len(attachments) > 0 {
+			fmt.Printf("Attached %d file(s)\n", len(attachments))
+		}
 		if len(recipients) > 0 {
 			fmt.Printf("Notified %d user(s)\n", len(recipients))
 		}
@@ -253,7 +331,7 @@ var threadsUpdateCmd = &cobra.Command{
 			return fmt.Errorf("no updates specified; use --title or --content flags")
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		thread, err := client.UpdateThread(threadID, updates)
 		if err != nil {
 			return fmt.Errorf("failed to update thread: %w", err)
@@ -283,7 +361,11 @@ var threadsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		if err := confirmAction(fmt.Sprintf("Delete thread %d and all of its comments?", threadID)); err != nil {
+			return err
+		}
+
+		client := newClient(token)
 		if err := client.DeleteThread(threadID); err != nil {
 			return fmt.Errorf("failed to delete thread: %w", err)
 		}
@@ -309,7 +391,7 @@ var threadsPinCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.PinThread(threadID); err != nil {
 			return fmt.Errorf("failed to pin thread: %w", err)
 		}
@@ -335,7 +417,7 @@ var threadsUnpinCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnpinThread(threadID); err != nil {
 			return fmt.Errorf("failed to unpin thread: %w", err)
 		}
@@ -361,7 +443,7 @@ var threadsStarCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.StarThread(threadID); err != nil {
 			return fmt.Errorf("failed to star thread: %w", err)
 		}
@@ -387,7 +469,7 @@ var threadsUnstarCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnstarThread(threadID); err != nil {
 			return fmt.Errorf("failed to unstar thread: %w", err)
 		}
@@ -413,7 +495,7 @@ var threadsArchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.ArchiveThread(threadID); err != nil {
 			return fmt.Errorf("failed to archive thread: %w", err)
 		}
@@ -439,7 +521,7 @@ var threadsUnarchiveCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		if err := client.UnarchiveThread(threadID); err != nil {
 			return fmt.Errorf("failed to unarchive thread: %w", err)
 		}
@@ -467,7 +549,7 @@ var commentsUpdateCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		comment, err := client.UpdateComment(commentID, content)
 		if err != nil {
 			return fmt.Errorf("failed to update comment: %w", err)
@@ -496,7 +578,11 @@ var commentsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		if err := confirmAction(fmt.Sprintf("Delete comment %d?", commentID)); err != nil {
+			return err
+		}
+
+		client := newClient(token)
 		if err := client.DeleteComment(commentID); err != nil {
 			return fmt.Errorf("failed to delete comment: %w", err)
 		}
@@ -512,18 +598,393 @@ var commentsCmd = &cobra.Command{
 	Long:  `Manage comments on threads.`,
 }
 
+var threadsFollowCmd = &cobra.Command{
+	Use:   "follow [thread-id]",
+	Short: "Follow a thread",
+	Long:  `Follow a thread to get notified about new comments.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.FollowThread(threadID); err != nil {
+			return fmt.Errorf("failed to follow thread: %w", err)
+		}
+
+		fmt.Printf("Thread %d followed\n", threadID)
+		return nil
+	},
+}
+
+var threadsUnfollowCmd = &cobra.Command{
+	Use:   "unfollow [thread-id]",
+	Short: "Unfollow a thread",
+	Long:  `Stop following a thread so new comments no longer notify you.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.UnfollowThread(threadID); err != nil {
+			return fmt.Errorf("failed to unfollow thread: %w", err)
+		}
+
+		fmt.Printf("Thread %d unfollowed\n", threadID)
+		return nil
+	},
+}
+
+var threadsMuteCmd = &cobra.Command{
+	Use:   "mute [thread-id]",
+	Short: "Mute a thread",
+	Long:  `Mute notifications from a thread.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.MuteThread(threadID); err != nil {
+			return fmt.Errorf("failed to mute thread: %w", err)
+		}
+
+		fmt.Printf("Thread %d muted successfully\n", threadID)
+		return nil
+	},
+}
+
+var threadsUnmuteCmd = &cobra.Command{
+	Use:   "unmute [thread-id]",
+	Short: "Unmute a thread",
+	Long:  `Unmute notifications from a thread.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.UnmuteThread(threadID); err != nil {
+			return fmt.Errorf("failed to unmute thread: %w", err)
+		}
+
+		fmt.Printf("Thread %d unmuted successfully\n", threadID)
+		return nil
+	},
+}
+
+var threadsMarkReadCmd = &cobra.Command{
+	Use:   "mark-read [thread-id]",
+	Short: "Mark a thread as read",
+	Long:  `Mark all comments in a thread as read.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.MarkThreadRead(threadID); err != nil {
+			return fmt.Errorf("failed to mark thread as read: %w", err)
+		}
+
+		fmt.Printf("Thread %d marked as read\n", threadID)
+		return nil
+	},
+}
+
+var threadsMarkUnreadCmd = &cobra.Command{
+	Use:   "mark-unread [thread-id]",
+	Short: "Mark a thread as unread",
+	Long:  `Mark a thread as unread.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.MarkThreadUnread(threadID); err != nil {
+			return fmt.Errorf("failed to mark thread as unread: %w", err)
+		}
+
+		fmt.Printf("Thread %d marked as unread\n", threadID)
+		return nil
+	},
+}
+
+var threadsMarkAllReadCmd = &cobra.Command{
+	Use:   "mark-all-read [channel-id]",
+	Short: "Mark all threads in a channel as read",
+	Long:  `Mark every thread in a channel as read.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid channel ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		if err := client.MarkAllThreadsRead(channelID); err != nil {
+			return fmt.Errorf("failed to mark threads as read: %w", err)
+		}
+
+		fmt.Printf("All threads in channel %d marked as read\n", channelID)
+		return nil
+	},
+}
+
+var threadsMoveCmd = &cobra.Command{
+	Use:   "move [thread-id] [channel-id]",
+	Short: "Move a thread to another channel",
+	Long:  `Move a thread, with its comments, to another channel in the same workspace.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid thread ID: %w", err)
+		}
+		channelID, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid channel ID: %w", err)
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		thread, err := client.GetThread(threadID)
+		if err != nil {
+			return fmt.Errorf("failed to get thread: %w", err)
+		}
+		if thread.ChannelID == channelID {
+			fmt.Printf("Thread %d is already in channel %d\n", threadID, channelID)
+			return nil
+		}
+
+		channel, err := client.GetChannel(channelID)
+		if err != nil {
+			return fmt.Errorf("failed to get channel: %w", err)
+		}
+		if channel.WorkspaceID != thread.WorkspaceID {
+			return fmt.Errorf("channel %d is in a different workspace than thread %d", channelID, threadID)
+		}
+
+		moved, err := client.UpdateThread(threadID, map[string]interface{}{"channel_id": channelID})
+		if err != nil {
+			return fmt.Errorf("failed to move thread: %w", err)
+		}
+
+		fmt.Printf("Thread %d moved to #%s\n", moved.ID, channel.Name)
+		fmt.Println(api.ThreadURL(thread.WorkspaceID, channelID, threadID))
+		return nil
+	},
+}
+
+var threadsMergeCmd = &cobra.Command{
+	Use:   "merge [source-thread-id] [dest-thread-id]",
+	Short: "Merge a thread into another thread",
+	Long: `Copy the post and comments of the source thread into the destination thread as
+comments, each noting its original author and time and linking to the original.
+A link to the destination is then posted in the source thread and the source is
+archived, unless --keep-source is given.
+
+Only text is copied: attachments and reactions on the source post and comments
+are not carried over.
+
+Copies already present in the destination are skipped, so a merge that stopped
+partway can be re-run without duplicating comments.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid source thread ID: %w", err)
+		}
+		destID, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid destination thread ID: %w", err)
+		}
+		if srcID == destID {
+			return fmt.Errorf("cannot merge a thread into itself")
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		src, err := client.GetThread(srcID)
+		if err != nil {
+			return fmt.Errorf("failed to get source thread: %w", err)
+		}
+		dest, err := client.GetThread(destID)
+		if err != nil {
+			return fmt.Errorf("failed to get destination thread: %w", err)
+		}
+		if src.WorkspaceID != dest.WorkspaceID {
+			return fmt.Errorf("threads %d and %d are in different workspaces", srcID, destID)
+		}
+
+		comments, err := client.GetComments(srcID)
+		if err != nil {
+			return fmt.Errorf("failed to get comments: %w", err)
+		}
+
+		dir, err := loadDirectory(client, src.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := client.GetComments(destID)
+		if err != nil {
+			return fmt.Errorf("failed to get destination comments: %w", err)
+		}
+		copied := func(url string) bool {
+			for _, c := range existing {
+				if strings.Contains(c.Content, "("+url+")") {
+					return true
+				}
+			}
+			return false
+		}
+
+		srcURL := api.ThreadURL(src.WorkspaceID, src.ChannelID, src.ID)
+		if !copied(srcURL) {
+			header := fmt.Sprintf("_Merged from [%s](%s). Originally posted by %s on %s_\n\n",
+				src.Title, srcURL, dir.userName(src.Creator), time.Unix(src.PostedTS, 0).Format("2006-01-02 15:04"))
+			if _, err := client.PostComment(destID, header+src.Content, nil); err != nil {
+				return fmt.Errorf("failed to copy thread post: %w", err)
+			}
+		}
+
+		// The link back to the destination is left by an earlier run of
+		// this merge and is not copied.
+		destURL := api.ThreadURL(dest.WorkspaceID, dest.ChannelID, dest.ID)
+		linked := false
+		var n, skipped int
+		for _, c := range comments {
+			if strings.Contains(c.Content, "("+destURL+")") {
+				linked = true
+				continue
+			}
+			commentURL := api.CommentURL(src.WorkspaceID, src.ChannelID, src.ID, c.ID)
+			if copied(commentURL) {
+				skipped++
+				continue
+			}
+			content := fmt.Sprintf("_[Originally posted](%s) by %s on %s_\n\n%s",
+				commentURL, dir.userName(c.Creator), time.Unix(c.PostedTS, 0).Format("2006-01-02 15:04"), c.Content)
+			if _, err := client.PostComment(destID, content, nil); err != nil {
+				return fmt.Errorf("failed to copy comment %d (%d of %d copied; re-run to resume): %w", c.ID, n+skipped, len(comments), err)
+			}
+			n++
+		}
+
+		if !linked {
+			if _, err := client.PostComment(srcID, fmt.Sprintf("_This thread was merged into [%s](%s)._", dest.Title, destURL), nil); err != nil {
+				return fmt.Errorf("failed to link source thread: %w", err)
+			}
+		}
+
+		if !mergeKeepSourceFlag {
+			if err := client.ArchiveThread(srcID); err != nil {
+				return fmt.Errorf("failed to archive source thread: %w", err)
+			}
+		}
+
+		fmt.Printf("Merged thread %d into thread %d (%d comment(s) copied", srcID, destID, n)
+		if skipped > 0 {
+			fmt.Printf(", %d already present", skipped)
+		}
+		fmt.Println(")")
+		if !mergeKeepSourceFlag {
+			fmt.Printf("Thread %d archived\n", srcID)
+		}
+		fmt.Println(destURL)
+		return nil
+	},
+}
+
 func init() {
 	threadsUpdateCmd.Flags().StringVar(&titleFlag, "title", "", "Thread title")
 	threadsUpdateCmd.Flags().StringVar(&contentFlag, "content", "", "Thread content")
 
-	threadsCreateCmd.Flags().StringVar(&createNotifyFlag, "notify", "", "Comma-separated user IDs to notify")
-	threadsReplyCmd.Flags().StringVar(&replyNotifyFlag, "notify", "", "Comma-separated user IDs to notify")
+	threadsListCmd.Flags().BoolVar(&offlineFlag, "offline", false, "Read from the local mirror instead of the API")
+	threadsListCmd.Flags().StringVar(&mirrorPathFlag, "db", "", "Path to the mirror database (with --offline)")
+	threadsShowCmd.Flags().BoolVar(&offlineFlag, "offline", false, "Read from the local mirror instead of the API")
+	threadsShowCmd.Flags().StringVar(&mirrorPathFlag, "db", "", "Path to the mirror database (with --offline)")
+
+	threadsCreateCmd.Flags().StringVar(&createNotifyFlag, "notify", "", "Comma-separated users or groups to notify")
+	threadsReplyCmd.Flags().StringVar(&replyNotifyFlag, "notify", "", "Comma-separated users or groups to notify")
+	threadsCreateCmd.Flags().IntVar(&notifyConfirmOverFlag, "confirm-over", 20, "Ask for confirmation when notifying more users than this (0 to never ask)")
+	threadsReplyCmd.Flags().IntVar(&notifyConfirmOverFlag, "confirm-over", 20, "Ask for confirmation when notifying more users than this (0 to never ask)")
+	threadsCreateCmd.Flags().StringArrayVar(&attachFlag, "attach", nil, "File to attach (repeatable, globs allowed)")
+	threadsReplyCmd.Flags().StringArrayVar(&attachFlag, "attach", nil, "File to attach (repeatable, globs allowed)")
+	threadsMergeCmd.Flags().BoolVar(&mergeKeepSourceFlag, "keep-source", false, "Do not archive the source thread")
 
 	threadsCmd.AddCommand(threadsListCmd)
 	threadsCmd.AddCommand(threadsShowCmd)
 	threadsCmd.AddCommand(threadsReplyCmd)
 	threadsCmd.AddCommand(threadsCreateCmd)
 	threadsCmd.AddCommand(threadsUpdateCmd)
+	threadsCmd.AddCommand(threadsMoveCmd)
+	threadsCmd.AddCommand(threadsFollowCmd)
+	threadsCmd.AddCommand(threadsUnfollowCmd)
+	threadsCmd.AddCommand(threadsMuteCmd)
+	threadsCmd.AddCommand(threadsUnmuteCmd)
+	threadsCmd.AddCommand(threadsMarkReadCmd)
+	threadsCmd.AddCommand(threadsMarkUnreadCmd)
+	threadsCmd.AddCommand(threadsMarkAllReadCmd)
+	threadsCmd.AddCommand(threadsMergeCmd)
 	threadsCmd.AddCommand(threadsDeleteCmd)
 	threadsCmd.AddCommand(threadsPinCmd)
 	threadsCmd.AddCommand(threadsUnpinCmd)
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/auth"
+	"github.com/intelligrit/twist-cli/internal/transcript"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag string
+	exportSinceFlag  string
+	exportUntilFlag  string
+	exportOutputFlag string
+	exportWithFlag   string
+	exportDirFlag    string
+)
+
+const exportPageSize = 100
+
+var conversationsExportCmd = &cobra.Command{
+	Use:   "export [conversation-id]",
+	Short: "Export a conversation as a transcript",
+	Long: `Export the messages of a conversation as a transcript in txt, md, json or
+html format, with user names resolved and attachment details included.
+
+Use --with instead of a conversation ID to export every conversation that
+includes a given user; each is written to its own file in --dir.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(transcript.Formats, exportFormatFlag) {
+			return fmt.Errorf("invalid --format %q: must be txt, md, json or html", exportFormatFlag)
+		}
+		if (len(args) == 1) == (exportWithFlag != "") {
+			return fmt.Errorf("give either a conversation ID or --with")
+		}
+
+		var since, until *time.Time
+		if exportSinceFlag != "" {
+			t, err := time.ParseInLocation("2006-01-02", exportSinceFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --since date (use YYYY-MM-DD): %w", err)
+			}
+			since = &t
+		}
+		if exportUntilFlag != "" {
+			t, err := time.ParseInLocation("2006-01-02", exportUntilFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --until date (use YYYY-MM-DD): %w", err)
+			}
+			until = &t
+		}
+
+		token, err := auth.GetToken(tokenFlag)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		client := newClient(token)
+		dir, err := loadDirectory(client)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			conversationID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid conversation ID: %w", err)
+			}
+			conv, err := client.GetConversation(conversationID)
+			if err != nil {
+				return fmt.Errorf("failed to get conversation: %w", err)
+			}
+			t, err := buildTranscript(client, dir, conv, since, until)
+			if err != nil {
+				return err
+			}
+
+			out := io.Writer(os.Stdout)
+			if exportOutputFlag != "" {
+				f, err := os.Create(exportOutputFlag)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			if err := transcript.Write(out, exportFormatFlag, t); err != nil {
+				return fmt.Errorf("failed to write transcript: %w", err)
+			}
+			if exportOutputFlag != "" {
+				fmt.Fprintf(os.Stderr, "✓ Exported %d messages to %s\n", len(t.Messages), exportOutputFlag)
+			}
+			return nil
+		}
+
+		withID, err := dir.findUser(exportWithFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --with: %w", err)
+		}
+		conversations, err := client.GetConversations()
+		if err != nil {
+			return fmt.Errorf("failed to get conversations: %w", err)
+		}
+
+		if err := os.MkdirAll(exportDirFlag, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		exported := 0
+		for i := range conversations {
+			conv := &conversations[i]
+			if !slices.Contains(conv.UserIDs, withID) {
+				continue
+			}
+			t, err := buildTranscript(client, dir, conv, since, until)
+			if err != nil {
+				return err
+			}
+
+			path := filepath.Join(exportDirFlag, fmt.Sprintf("conversation-%d.%s", conv.ID, exportFormatFlag))
+			f, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			err = transcript.Write(f, exportFormatFlag, t)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("failed to write transcript: %w", err)
+			}
+			fmt.Printf("✓ %s (%d messages)\n", path, len(t.Messages))
+			exported++
+		}
+
+		if exported == 0 {
+			fmt.Printf("No conversations with %s found.\n", dir.userName(withID))
+			return nil
+		}
+		fmt.Printf("\nExported %d conversations with %s to %s\n", exported, dir.userName(withID), exportDirFlag)
+		return nil
+	},
+}
+
+// buildTranscript pages through a conversation's messages, oldest first,
+// keeping those posted between since and the end of the until day.
+func buildTranscript(client *api.Client, dir *directory, conv *api.Conversation, since, until *time.Time) (*transcript.Transcript, error) {
+	t := &transcript.Transcript{
+		ConversationID: conv.ID,
+		WorkspaceID:    conv.WorkspaceID,
+		Title:          conv.Title,
+		Since:          since,
+		Until:          until,
+		ExportedAt:     time.Now(),
+		Messages:       []transcript.Message{},
+	}
+	for _, uid := range conv.UserIDs {
+		t.Participants = append(t.Participants, dir.userName(uid))
+	}
+
+	var newerThan int64
+	if since != nil {
+		newerThan = since.Unix() - 1
+	}
+	var untilTS int64
+	if until != nil {
+		untilTS = until.AddDate(0, 0, 1).Unix()
+	}
+
+	seen := make(map[int]bool)
+	for {
+		page, err := client.GetConversationMessagesPage(conv.ID, map[string]interface{}{
+			"newer_than_ts": newerThan,
+			"limit":         exportPageSize,
+			"order_by":      "asc",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages of conversation %d: %w", conv.ID, err)
+		}
+
+		added := 0
+		for _, m := range page {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			added++
+			if m.CreatedTS > newerThan {
+				newerThan = m.CreatedTS
+			}
+			if (since != nil && m.CreatedTS < since.Unix()) || (untilTS != 0 && m.CreatedTS >= untilTS) {
+				continue
+			}
+			t.Messages = append(t.Messages, transcriptMessage(dir, m))
+		}
+
+		// Messages sharing the last timestamp of a full page may be split
+		// across pages, so step back a second and rely on seen to dedupe.
+		if added == 0 || len(page) < exportPageSize || (untilTS != 0 && newerThan >= untilTS) {
+			break
+		}
+		newerThan--
+	}
+
+	sort.SliceStable(t.Messages, func(i, j int) bool {
+		if !t.Messages[i].Time.Equal(t.Messages[j].Time) {
+			return t.Messages[i].Time.Before(t.Messages[j].Time)
+		}
+		return t.Messages[i].ID < t.Messages[j].ID
+	})
+	return t, nil
+}
+
+func transcriptMessage(dir *directory, m api.ConversationMessage) transcript.Message {
+	msg := transcript.Message{
+		ID:       m.ID,
+		AuthorID: m.UserID,
+		Author:   dir.userName(m.UserID),
+		Time:     time.Unix(m.CreatedTS, 0),
+		Content:  m.Content,
+	}
+	for _, a := range m.Attachments {
+		msg.Attachments = append(msg.Attachments, transcript.Attachment{
+			ID:       a.ID,
+			Title:    a.Title,
+			URL:      a.URL,
+			Size:     a.Size,
+			MimeType: a.MimeType,
+		})
+	}
+	return msg
+}
+
+func init() {
+	conversationsExportCmd.Flags().StringVar(&exportFormatFlag, "format", "txt", "Transcript format: txt, md, json or html")
+	conversationsExportCmd.Flags().StringVar(&exportSinceFlag, "since", "", "Only messages from this date on (YYYY-MM-DD)")
+	conversationsExportCmd.Flags().StringVar(&exportUntilFlag, "until", "", "Only messages up to and including this date (YYYY-MM-DD)")
+	conversationsExportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "Write the transcript to this file instead of stdout")
+	conversationsExportCmd.Flags().StringVar(&exportWithFlag, "with", "", "Export every conversation with this user (ID, name or email)")
+	conversationsExportCmd.Flags().StringVar(&exportDirFlag, "dir", ".", "Directory to write transcripts to when using --with")
+
+	conversationsCmd.AddCommand(conversationsExportCmd)
+}
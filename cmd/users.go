@@ -7,7 +7,6 @@ import (
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
-	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
@@ -33,7 +32,7 @@ var usersListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		users, err := client.GetWorkspaceUsers(workspaceID)
 		if err != nil {
 			return fmt.Errorf("failed to get users: %w", err)
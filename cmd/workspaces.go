@@ -6,7 +6,6 @@ import (
 	"text/tabwriter"
 
 	"github.com/intelligrit/twist-cli/internal/auth"
-	"github.com/intelligrit/twist-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +25,7 @@ var workspacesListCmd = &cobra.Command{
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
-		client := api.NewClient(token)
+		client := newClient(token)
 		workspaces, err := client.GetWorkspaces()
 		if err != nil {
 			return fmt.Errorf("failed to get workspaces: %w", err)
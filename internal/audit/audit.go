@@ -0,0 +1,208 @@
+// Package audit records every mutating API call made by the CLI in a JSON
+// lines file in the config directory.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intelligrit/twist-cli/internal/config"
+)
+
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Profile     string    `json:"profile"`
+	Method      string    `json:"method"`
+	Endpoint    string    `json:"endpoint"`
+	PayloadHash string    `json:"payload_hash,omitempty"`
+	PayloadSize int64     `json:"payload_size"`
+	Status      int       `json:"status,omitempty"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	Note        string    `json:"note,omitempty"`
+}
+
+// Profile identifies the credentials a change was made with by a short hash
+// of the API token, so that entries made with different tokens can be told
+// apart without the log holding the token itself.
+func Profile(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:6])
+}
+
+// DefaultPath returns the location of the audit log in the config directory.
+func DefaultPath() (string, error) {
+	return config.Path("audit.jsonl")
+}
+
+var writeMu sync.Mutex
+
+// Append adds an entry to the log at path.
+func Append(path string, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns every entry of the log at path, oldest first. A missing log
+// has no entries.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Transport records every request other than GET and HEAD made through it.
+// Payloads are hashed as they are sent rather than buffered, so large
+// uploads are not held in memory. Failures to write the log are reported on
+// Warn and do not fail the request.
+type Transport struct {
+	Next     http.RoundTripper
+	Path     string
+	BasePath string
+	Profile  string
+	Warn     io.Writer
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.Next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	p := &pendingEntry{t: t, left: 1, e: Entry{
+		Time:     start,
+		Profile:  t.Profile,
+		Method:   req.Method,
+		Endpoint: strings.TrimPrefix(req.URL.Path, t.BasePath),
+	}}
+
+	// The transport may still be sending the body after RoundTrip returns,
+	// so its hash and size are only recorded once the body is closed.
+	if req.Body != nil {
+		p.left++
+		req.Body = &hashingBody{ReadCloser: req.Body, h: sha256.New(), onClose: func(sum []byte, n int64) {
+			p.finish(func(e *Entry) {
+				e.PayloadHash = "sha256:" + hex.EncodeToString(sum)
+				e.PayloadSize = n
+			})
+		}}
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+
+	p.finish(func(e *Entry) {
+		e.DurationMS = time.Since(start).Milliseconds()
+		switch {
+		case err != nil:
+			e.Result = "error"
+			e.Error = err.Error()
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			e.Status = resp.StatusCode
+			e.Result = "ok"
+		default:
+			e.Status = resp.StatusCode
+			e.Result = "error"
+		}
+	})
+	return resp, err
+}
+
+// pendingEntry is written to the log when both the response and the end of
+// the request body have been seen, whichever comes last.
+type pendingEntry struct {
+	mu   sync.Mutex
+	t    *Transport
+	e    Entry
+	left int
+}
+
+func (p *pendingEntry) finish(update func(*Entry)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	update(&p.e)
+	p.left--
+	if p.left > 0 {
+		return
+	}
+	if err := Append(p.t.Path, p.e); err != nil && p.t.Warn != nil {
+		fmt.Fprintf(p.t.Warn, "warning: %v\n", err)
+	}
+}
+
+type hashingBody struct {
+	io.ReadCloser
+	mu      sync.Mutex
+	h       hash.Hash
+	n       int64
+	once    sync.Once
+	onClose func(sum []byte, n int64)
+}
+
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	b.h.Write(p[:n])
+	b.n += int64(n)
+	b.mu.Unlock()
+	return n, err
+}
+
+func (b *hashingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.mu.Lock()
+		sum, n := b.h.Sum(nil), b.n
+		b.mu.Unlock()
+		b.onClose(sum, n)
+	})
+	return err
+}
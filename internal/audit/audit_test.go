@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			http.Error(w, "no", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := &http.Client{Transport: &Transport{
+		Next:     http.DefaultTransport,
+		Path:     path,
+		BasePath: "/api/v3",
+		Profile:  Profile("secret"),
+	}}
+
+	do := func(method, endpoint, body string) {
+		t.Helper()
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req, err := http.NewRequest(method, srv.URL+"/api/v3"+endpoint, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	do("GET", "/threads/get", "")
+	do("POST", "/threads/add", `{"title":"hi"}`)
+	do("POST", "/threads/fail", `{}`)
+	do("DELETE", "/threads/remove", "")
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3 (GET is not recorded): %+v", len(entries), entries)
+	}
+
+	sum := sha256.Sum256([]byte(`{"title":"hi"}`))
+	tests := []struct {
+		method, endpoint, hash, result string
+		size                           int64
+		status                         int
+	}{
+		{"POST", "/threads/add", "sha256:" + hex.EncodeToString(sum[:]), "ok", 14, 200},
+		{"POST", "/threads/fail", "", "error", 2, 403},
+		{"DELETE", "/threads/remove", "", "ok", 0, 200},
+	}
+	for i, tt := range tests {
+		e := entries[i]
+		if e.Method != tt.method || e.Endpoint != tt.endpoint || e.Result != tt.result || e.Status != tt.status || e.PayloadSize != tt.size {
+			t.Errorf("entry %d = %+v, want %s %s %s status %d size %d", i, e, tt.method, tt.endpoint, tt.result, tt.status, tt.size)
+		}
+		if tt.hash != "" && e.PayloadHash != tt.hash {
+			t.Errorf("entry %d hash = %s, want %s", i, e.PayloadHash, tt.hash)
+		}
+		if e.Profile != Profile("secret") {
+			t.Errorf("entry %d profile = %q, want %q", i, e.Profile, Profile("secret"))
+		}
+	}
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, errors.New("connection refused")
+}
+
+func TestTransportError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := &http.Client{Transport: &Transport{Next: failingTransport{}, Path: path}}
+
+	req, _ := http.NewRequest("POST", "http://example.invalid/x", strings.NewReader("payload"))
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("request succeeded")
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Result != "error" || !strings.Contains(entries[0].Error, "connection refused") {
+		t.Fatalf("entries = %+v, want one error entry", entries)
+	}
+}
+
+func TestTransportConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := &http.Client{Transport: &Transport{Next: http.DefaultTransport, Path: path}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Post(srv.URL, "text/plain", strings.NewReader(strings.Repeat("x", 100000)))
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 20 {
+		t.Fatalf("got %d entries, want 20", len(entries))
+	}
+	for _, e := range entries {
+		if e.PayloadSize != 100000 {
+			t.Errorf("entry payload size = %d, want 100000", e.PayloadSize)
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+	a, b := Profile("token-a"), Profile("token-b")
+	if a == b || a != Profile("token-a") {
+		t.Errorf("Profile is not a stable per-token value: %q %q", a, b)
+	}
+	if strings.Contains(a, "token-a") || len(a) != len("token:")+12 {
+		t.Errorf("Profile(%q) = %q", "token-a", a)
+	}
+}
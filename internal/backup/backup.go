@@ -0,0 +1,382 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+// FormatVersion is bumped whenever the on-disk layout changes incompatibly.
+//
+// Layout of a backup directory:
+//
+//	manifest.json
+//	workspace.json
+//	users.json
+//	groups.json
+//	channels/<channel-id>/channel.json
+//	channels/<channel-id>/threads/<thread-id>.json
+//	conversations/<conversation-id>.json
+//	attachments/<attachment-id>/<title>   (only with DownloadAttachments)
+const FormatVersion = 1
+
+type Manifest struct {
+	Version       int           `json:"version"`
+	WorkspaceID   int           `json:"workspace_id"`
+	StartedTS     int64         `json:"started_ts"`
+	CompletedTS   int64         `json:"completed_ts"`
+	LastBackupTS  int64         `json:"last_backup_ts"`
+	Threads       map[int]int64 `json:"threads"`
+	Conversations map[int]int64 `json:"conversations"`
+}
+
+type ThreadRecord struct {
+	Thread      api.Thread       `json:"thread"`
+	Reactions   []api.Reaction   `json:"reactions"`
+	Attachments []api.Attachment `json:"attachments"`
+	Comments    []CommentRecord  `json:"comments"`
+}
+
+type CommentRecord struct {
+	Comment     api.Comment      `json:"comment"`
+	Reactions   []api.Reaction   `json:"reactions"`
+	Attachments []api.Attachment `json:"attachments"`
+}
+
+type ConversationRecord struct {
+	Conversation api.Conversation          `json:"conversation"`
+	Messages     []api.ConversationMessage `json:"messages"`
+	Attachments  []api.Attachment          `json:"attachments"`
+}
+
+type Options struct {
+	Dir                 string
+	WorkspaceID         int
+	Full                bool
+	DownloadAttachments bool
+	Log                 io.Writer
+}
+
+type Stats struct {
+	Channels             int
+	ThreadsSaved         int
+	ThreadsSkipped       int
+	ConversationsSaved   int
+	ConversationsSkipped int
+	Attachments          int
+}
+
+func ManifestPath(dir string) string {
+	return filepath.Join(dir, "manifest.json")
+}
+
+func ChannelDir(dir string, channelID int) string {
+	return filepath.Join(dir, "channels", strconv.Itoa(channelID))
+}
+
+func ThreadPath(dir string, channelID, threadID int) string {
+	return filepath.Join(ChannelDir(dir, channelID), "threads", strconv.Itoa(threadID)+".json")
+}
+
+func ConversationPath(dir string, conversationID int) string {
+	return filepath.Join(dir, "conversations", strconv.Itoa(conversationID)+".json")
+}
+
+func ReadManifest(dir string) (*Manifest, error) {
+	var m Manifest
+	if err := readJSON(ManifestPath(dir), &m); err != nil {
+		return nil, err
+	}
+	if m.Version > FormatVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than supported version %d", m.Version, FormatVersion)
+	}
+	if m.Threads == nil {
+		m.Threads = make(map[int]int64)
+	}
+	if m.Conversations == nil {
+		m.Conversations = make(map[int]int64)
+	}
+	return &m, nil
+}
+
+// Run walks the workspace and writes it to opts.Dir. Threads and
+// conversations already recorded in the manifest with an unchanged
+// last-updated timestamp are skipped, which makes an interrupted run
+// resumable and a repeated run incremental.
+func Run(client *api.Client, opts Options) (*Stats, error) {
+	log := opts.Log
+	if log == nil {
+		log = io.Discard
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	manifest, err := ReadManifest(opts.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if manifest != nil && manifest.WorkspaceID != 0 && manifest.WorkspaceID != opts.WorkspaceID {
+		return nil, fmt.Errorf("backup directory belongs to workspace %d", manifest.WorkspaceID)
+	}
+	if manifest == nil || opts.Full {
+		manifest = &Manifest{
+			Threads:       make(map[int]int64),
+			Conversations: make(map[int]int64),
+		}
+	}
+	manifest.Version = FormatVersion
+	manifest.WorkspaceID = opts.WorkspaceID
+	manifest.StartedTS = time.Now().Unix()
+	if err := writeJSON(ManifestPath(opts.Dir), manifest); err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+
+	workspaces, err := client.GetWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+	for _, ws := range workspaces {
+		if ws.ID == opts.WorkspaceID {
+			if err := writeJSON(filepath.Join(opts.Dir, "workspace.json"), ws); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	users, err := client.GetWorkspaceUsers(opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	if err := writeJSON(filepath.Join(opts.Dir, "users.json"), users); err != nil {
+		return nil, err
+	}
+
+	groups, err := client.GetGroups(opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	if err := writeJSON(filepath.Join(opts.Dir, "groups.json"), groups); err != nil {
+		return nil, err
+	}
+
+	channels, err := client.GetChannels(opts.WorkspaceID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+	archived, err := client.GetChannels(opts.WorkspaceID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived channels: %w", err)
+	}
+	channels = append(channels, archived...)
+
+	seen := make(map[int]bool)
+	for _, ch := range channels {
+		if seen[ch.ID] {
+			continue
+		}
+		seen[ch.ID] = true
+		stats.Channels++
+
+		fmt.Fprintf(log, "Channel #%d %s\n", ch.ID, ch.Name)
+		if err := writeJSON(filepath.Join(ChannelDir(opts.Dir, ch.ID), "channel.json"), ch); err != nil {
+			return stats, err
+		}
+
+		threads, err := client.GetThreads(ch.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get threads for channel %d: %w", ch.ID, err)
+		}
+
+		for _, t := range threads {
+			path := ThreadPath(opts.Dir, ch.ID, t.ID)
+			if ts, ok := manifest.Threads[t.ID]; ok && ts >= t.LastUpdatedTS && fileExists(path) {
+				stats.ThreadsSkipped++
+				continue
+			}
+
+			record, err := fetchThread(client, t)
+			if err != nil {
+				return stats, err
+			}
+			if err := writeJSON(path, record); err != nil {
+				return stats, err
+			}
+			if opts.DownloadAttachments {
+				n, err := downloadAttachments(client, opts.Dir, threadAttachments(record))
+				stats.Attachments += n
+				if err != nil {
+					return stats, err
+				}
+			}
+
+			manifest.Threads[t.ID] = t.LastUpdatedTS
+			if err := writeJSON(ManifestPath(opts.Dir), manifest); err != nil {
+				return stats, err
+			}
+			stats.ThreadsSaved++
+			fmt.Fprintf(log, "  Thread #%d (%d comments)\n", t.ID, len(record.Comments))
+		}
+	}
+
+	conversations, err := client.GetConversations()
+	if err != nil {
+		return stats, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	for _, conv := range conversations {
+		if conv.WorkspaceID != opts.WorkspaceID {
+			continue
+		}
+		path := ConversationPath(opts.Dir, conv.ID)
+		if ts, ok := manifest.Conversations[conv.ID]; ok && ts >= conv.LastActiveTS && fileExists(path) {
+			stats.ConversationsSkipped++
+			continue
+		}
+
+		messages, err := client.GetConversationMessages(conv.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get messages for conversation %d: %w", conv.ID, err)
+		}
+		attachments, err := client.GetAttachments("conversation", conv.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get attachments for conversation %d: %w", conv.ID, err)
+		}
+
+		record := ConversationRecord{Conversation: conv, Messages: messages, Attachments: attachments}
+		if err := writeJSON(path, record); err != nil {
+			return stats, err
+		}
+		if opts.DownloadAttachments {
+			n, err := downloadAttachments(client, opts.Dir, attachments)
+			stats.Attachments += n
+			if err != nil {
+				return stats, err
+			}
+		}
+
+		manifest.Conversations[conv.ID] = conv.LastActiveTS
+		if err := writeJSON(ManifestPath(opts.Dir), manifest); err != nil {
+			return stats, err
+		}
+		stats.ConversationsSaved++
+		fmt.Fprintf(log, "Conversation #%d (%d messages)\n", conv.ID, len(messages))
+	}
+
+	manifest.CompletedTS = time.Now().Unix()
+	manifest.LastBackupTS = manifest.StartedTS
+	if err := writeJSON(ManifestPath(opts.Dir), manifest); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+func fetchThread(client *api.Client, t api.Thread) (*ThreadRecord, error) {
+	record := &ThreadRecord{Thread: t}
+
+	var err error
+	record.Reactions, err = client.GetReactions("thread", t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions for thread %d: %w", t.ID, err)
+	}
+	record.Attachments, err = client.GetAttachments("thread", t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments for thread %d: %w", t.ID, err)
+	}
+
+	comments, err := client.GetComments(t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments for thread %d: %w", t.ID, err)
+	}
+	for _, c := range comments {
+		cr := CommentRecord{Comment: c}
+		cr.Reactions, err = client.GetReactions("comment", c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reactions for comment %d: %w", c.ID, err)
+		}
+		cr.Attachments, err = client.GetAttachments("comment", c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attachments for comment %d: %w", c.ID, err)
+		}
+		record.Comments = append(record.Comments, cr)
+	}
+
+	return record, nil
+}
+
+func threadAttachments(record *ThreadRecord) []api.Attachment {
+	attachments := append([]api.Attachment{}, record.Attachments...)
+	for _, c := range record.Comments {
+		attachments = append(attachments, c.Attachments...)
+	}
+	return attachments
+}
+
+func downloadAttachments(client *api.Client, dir string, attachments []api.Attachment) (int, error) {
+	count := 0
+	for _, a := range attachments {
+		name := filepath.Base(a.Title)
+		if name == "" || name == "." || name == string(filepath.Separator) {
+			name = "file"
+		}
+		out := filepath.Join(dir, "attachments", strconv.Itoa(a.ID), name)
+		if fileExists(out) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return count, fmt.Errorf("failed to create attachment directory: %w", err)
+		}
+		if err := client.DownloadAttachment(a.ID, out); err != nil {
+			return count, fmt.Errorf("failed to download attachment %d: %w", a.ID, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes through a temporary file so an interrupted run never
+// leaves a truncated file behind.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
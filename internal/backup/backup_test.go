@@ -0,0 +1,219 @@
+package backup
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+// fakeTwist serves just enough of the API for a backup of workspace 1 with
+// one channel.
+type fakeTwist struct {
+	mu            sync.Mutex
+	threads       []api.Thread
+	comments      map[int][]api.Comment
+	conversations []api.Conversation
+	requests      map[string]int
+}
+
+func (f *fakeTwist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	f.requests[path+"?"+r.URL.RawQuery]++
+	id := func(key string) int {
+		n, _ := strconv.Atoi(r.URL.Query().Get(key))
+		return n
+	}
+
+	var v interface{} = []struct{}{}
+	switch path {
+	case "/workspaces/get":
+		v = []api.Workspace{{ID: 1, Name: "Acme"}}
+	case "/channels/get":
+		if r.URL.Query().Get("archived") == "" {
+			v = []api.Channel{{ID: 10, Name: "general", WorkspaceID: 1}}
+		}
+	case "/threads/get":
+		var threads []api.Thread
+		for _, t := range f.threads {
+			if t.ChannelID == id("channel_id") {
+				threads = append(threads, t)
+			}
+		}
+		v = threads
+	case "/comments/get":
+		v = f.comments[id("thread_id")]
+	case "/conversations/get":
+		v = f.conversations
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeTwist) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for req, c := range f.requests {
+		if strings.HasPrefix(req, prefix) {
+			n += c
+		}
+	}
+	return n
+}
+
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+func newFakeClient(t *testing.T, h http.Handler) *api.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	client := api.NewClient("test-token")
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return redirectTransport{target: target, next: next}
+	})
+	return client
+}
+
+func TestRunIncremental(t *testing.T) {
+	fake := &fakeTwist{
+		threads: []api.Thread{
+			{ID: 100, ChannelID: 10, WorkspaceID: 1, Title: "Plans", LastUpdatedTS: 1000},
+			{ID: 101, ChannelID: 10, WorkspaceID: 1, Title: "Notes", LastUpdatedTS: 1000},
+		},
+		comments: map[int][]api.Comment{
+			100: {{ID: 1, ThreadID: 100, Content: "first"}},
+		},
+		conversations: []api.Conversation{
+			{ID: 500, WorkspaceID: 1, LastActiveTS: 1000},
+			{ID: 600, WorkspaceID: 2, LastActiveTS: 1000},
+		},
+		requests: make(map[string]int),
+	}
+	client := newFakeClient(t, fake)
+	dir := t.TempDir()
+
+	stats, err := Run(client, Options{Dir: dir, WorkspaceID: 1})
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if stats.ThreadsSaved != 2 || stats.ThreadsSkipped != 0 {
+		t.Errorf("first run saved %d and skipped %d threads, want 2 and 0", stats.ThreadsSaved, stats.ThreadsSkipped)
+	}
+	if stats.ConversationsSaved != 1 {
+		t.Errorf("first run saved %d conversations, want only workspace 1's", stats.ConversationsSaved)
+	}
+	if fileExists(ConversationPath(dir, 600)) {
+		t.Error("conversation of another workspace was backed up")
+	}
+
+	// A new comment and a new thread, with nothing else about the channel
+	// changing.
+	fake.mu.Lock()
+	fake.threads[0].LastUpdatedTS = 2000
+	fake.comments[100] = append(fake.comments[100], api.Comment{ID: 2, ThreadID: 100, Content: "second"})
+	fake.threads = append(fake.threads, api.Thread{ID: 102, ChannelID: 10, WorkspaceID: 1, Title: "New", LastUpdatedTS: 2000})
+	fake.mu.Unlock()
+	commentFetches := fake.count("/comments/get?thread_id=101")
+
+	stats, err = Run(client, Options{Dir: dir, WorkspaceID: 1})
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if stats.ThreadsSaved != 2 || stats.ThreadsSkipped != 1 {
+		t.Errorf("second run saved %d and skipped %d threads, want 2 and 1", stats.ThreadsSaved, stats.ThreadsSkipped)
+	}
+	if stats.ConversationsSkipped != 1 {
+		t.Errorf("second run skipped %d conversations, want 1", stats.ConversationsSkipped)
+	}
+	if n := fake.count("/comments/get?thread_id=101"); n != commentFetches {
+		t.Errorf("unchanged thread 101 was fetched again")
+	}
+
+	var record ThreadRecord
+	if err := readJSON(ThreadPath(dir, 10, 100), &record); err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Comments) != 2 {
+		t.Errorf("thread 100 has %d comments in the backup, want 2", len(record.Comments))
+	}
+	if !fileExists(ThreadPath(dir, 10, 102)) {
+		t.Error("new thread 102 was not backed up")
+	}
+
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.CompletedTS == 0 || manifest.Threads[100] != 2000 || manifest.Threads[102] != 2000 {
+		t.Errorf("manifest = %+v, want completed with threads 100 and 102 at 2000", manifest)
+	}
+}
+
+func TestRunResumesAfterMissingFile(t *testing.T) {
+	fake := &fakeTwist{
+		threads:  []api.Thread{{ID: 100, ChannelID: 10, WorkspaceID: 1, LastUpdatedTS: 1000}},
+		comments: map[int][]api.Comment{},
+		requests: make(map[string]int),
+	}
+	client := newFakeClient(t, fake)
+	dir := t.TempDir()
+
+	if _, err := Run(client, Options{Dir: dir, WorkspaceID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// A thread recorded in the manifest whose file is gone is saved again.
+	if err := os.Remove(ThreadPath(dir, 10, 100)); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := Run(client, Options{Dir: dir, WorkspaceID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ThreadsSaved != 1 || !fileExists(ThreadPath(dir, 10, 100)) {
+		t.Errorf("missing thread file was not saved again (saved %d)", stats.ThreadsSaved)
+	}
+}
+
+func TestRunRefusesOtherWorkspace(t *testing.T) {
+	fake := &fakeTwist{comments: map[int][]api.Comment{}, requests: make(map[string]int)}
+	client := newFakeClient(t, fake)
+	dir := t.TempDir()
+
+	if _, err := Run(client, Options{Dir: dir, WorkspaceID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, full := range []bool{false, true} {
+		_, err := Run(client, Options{Dir: dir, WorkspaceID: 2, Full: full})
+		if err == nil || !strings.Contains(err.Error(), "belongs to workspace 1") {
+			t.Errorf("Run(Full: %v) into workspace 1's backup: error = %v", full, err)
+		}
+	}
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.WorkspaceID != 1 {
+		t.Errorf("manifest now belongs to workspace %d", manifest.WorkspaceID)
+	}
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+func ReadUsers(dir string) ([]api.User, error) {
+	var users []api.User
+	if err := readJSON(filepath.Join(dir, "users.json"), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func ReadChannels(dir string) ([]api.Channel, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "channels"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var channels []api.Channel
+	for _, e := range entries {
+		id, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		var ch api.Channel
+		if err := readJSON(filepath.Join(ChannelDir(dir, id), "channel.json"), &ch); err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	return channels, nil
+}
+
+// ReadThreads returns the saved threads of a channel ordered by posting time.
+func ReadThreads(dir string, channelID int) ([]ThreadRecord, error) {
+	threadsDir := filepath.Join(ChannelDir(dir, channelID), "threads")
+	entries, err := os.ReadDir(threadsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []ThreadRecord
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var record ThreadRecord
+		if err := readJSON(filepath.Join(threadsDir, e.Name()), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Thread.PostedTS < records[j].Thread.PostedTS })
+	return records, nil
+}
+
+func ReadConversations(dir string) ([]ConversationRecord, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "conversations"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []ConversationRecord
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var record ConversationRecord
+		if err := readJSON(filepath.Join(dir, "conversations", e.Name()), &record); err != nil {
+			return nil, fmt.Errorf("failed to read conversation: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Conversation.ID < records[j].Conversation.ID })
+	return records, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the directory holding local CLI state such as the offline
+// mirror. TWIST_CONFIG_DIR overrides the platform default.
+func Dir() (string, error) {
+	dir := os.Getenv("TWIST_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate config directory: %w", err)
+		}
+		dir = filepath.Join(base, "twist-cli")
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return dir, nil
+}
+
+// Path returns the location of a file inside the config directory.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,218 @@
+// Package emoji maps Slack/GitHub style :shortcodes: to Unicode emoji.
+package emoji
+
+import (
+	"fmt"
+	"strings"
+)
+
+var shortcodes = map[string]string{
+	"+1":                          "👍",
+	"thumbsup":                    "👍",
+	"-1":                          "👎",
+	"thumbsdown":                  "👎",
+	"ok_hand":                     "👌",
+	"clap":                        "👏",
+	"wave":                        "👋",
+	"raised_hands":                "🙌",
+	"pray":                        "🙏",
+	"muscle":                      "💪",
+	"point_up":                    "☝️",
+	"point_right":                 "👉",
+	"v":                           "✌️",
+	"crossed_fingers":             "🤞",
+	"handshake":                   "🤝",
+	"eyes":                        "👀",
+	"brain":                       "🧠",
+	"smile":                       "😄",
+	"smiley":                      "😃",
+	"grinning":                    "😀",
+	"grin":                        "😁",
+	"laughing":                    "😆",
+	"joy":                         "😂",
+	"rofl":                        "🤣",
+	"sweat_smile":                 "😅",
+	"slightly_smiling_face":       "🙂",
+	"upside_down_face":            "🙃",
+	"wink":                        "😉",
+	"blush":                       "😊",
+	"innocent":                    "😇",
+	"heart_eyes":                  "😍",
+	"star_struck":                 "🤩",
+	"kissing_heart":               "😘",
+	"yum":                         "😋",
+	"stuck_out_tongue":            "😛",
+	"thinking":                    "🤔",
+	"thinking_face":               "🤔",
+	"neutral_face":                "😐",
+	"expressionless":              "😑",
+	"no_mouth":                    "😶",
+	"smirk":                       "😏",
+	"unamused":                    "😒",
+	"roll_eyes":                   "🙄",
+	"grimacing":                   "😬",
+	"relieved":                    "😌",
+	"pensive":                     "😔",
+	"sleepy":                      "😪",
+	"sleeping":                    "😴",
+	"mask":                        "😷",
+	"nerd_face":                   "🤓",
+	"sunglasses":                  "😎",
+	"confused":                    "😕",
+	"worried":                     "😟",
+	"frowning":                    "😦",
+	"open_mouth":                  "😮",
+	"astonished":                  "😲",
+	"flushed":                     "😳",
+	"pleading_face":               "🥺",
+	"cry":                         "😢",
+	"sob":                         "😭",
+	"scream":                      "😱",
+	"disappointed":                "😞",
+	"sweat":                       "😓",
+	"weary":                       "😩",
+	"tired_face":                  "😫",
+	"triumph":                     "😤",
+	"rage":                        "😡",
+	"angry":                       "😠",
+	"exploding_head":              "🤯",
+	"partying_face":               "🥳",
+	"skull":                       "💀",
+	"poop":                        "💩",
+	"clown_face":                  "🤡",
+	"ghost":                       "👻",
+	"robot":                       "🤖",
+	"heart":                       "❤️",
+	"orange_heart":                "🧡",
+	"yellow_heart":                "💛",
+	"green_heart":                 "💚",
+	"blue_heart":                  "💙",
+	"purple_heart":                "💜",
+	"black_heart":                 "🖤",
+	"broken_heart":                "💔",
+	"sparkling_heart":             "💖",
+	"100":                         "💯",
+	"fire":                        "🔥",
+	"sparkles":                    "✨",
+	"star":                        "⭐",
+	"star2":                       "🌟",
+	"zap":                         "⚡",
+	"boom":                        "💥",
+	"tada":                        "🎉",
+	"confetti_ball":               "🎊",
+	"balloon":                     "🎈",
+	"gift":                        "🎁",
+	"trophy":                      "🏆",
+	"medal":                       "🏅",
+	"rocket":                      "🚀",
+	"bulb":                        "💡",
+	"memo":                        "📝",
+	"pushpin":                     "📌",
+	"paperclip":                   "📎",
+	"calendar":                    "📅",
+	"chart_with_upwards_trend":    "📈",
+	"lock":                        "🔒",
+	"key":                         "🔑",
+	"bell":                        "🔔",
+	"mega":                        "📣",
+	"hourglass":                   "⌛",
+	"alarm_clock":                 "⏰",
+	"coffee":                      "☕",
+	"beer":                        "🍺",
+	"beers":                       "🍻",
+	"pizza":                       "🍕",
+	"cake":                        "🍰",
+	"sunny":                       "☀️",
+	"rainbow":                     "🌈",
+	"snowflake":                   "❄️",
+	"white_check_mark":            "✅",
+	"heavy_check_mark":            "✔️",
+	"ballot_box_with_check":       "☑️",
+	"x":                           "❌",
+	"negative_squared_cross_mark": "❎",
+	"warning":                     "⚠️",
+	"no_entry":                    "⛔",
+	"question":                    "❓",
+	"exclamation":                 "❗",
+	"bangbang":                    "‼️",
+	"heavy_plus_sign":             "➕",
+	"heavy_minus_sign":            "➖",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"arrow_right":                 "➡️",
+	"arrow_left":                  "⬅️",
+	"repeat":                      "🔁",
+	"link":                        "🔗",
+	"speech_balloon":              "💬",
+	"bug":                         "🐛",
+	"hammer":                      "🔨",
+	"wrench":                      "🔧",
+	"gear":                        "⚙️",
+	"construction":                "🚧",
+	"rotating_light":              "🚨",
+	"stop_sign":                   "🛑",
+	"checkered_flag":              "🏁",
+	"dart":                        "🎯",
+	"moneybag":                    "💰",
+	"ship":                        "🚢",
+	"see_no_evil":                 "🙈",
+	"hear_no_evil":                "🙉",
+	"speak_no_evil":               "🙊",
+	"unicorn":                     "🦄",
+	"dog":                         "🐶",
+	"cat":                         "🐱",
+	"one":                         "1️⃣",
+	"two":                         "2️⃣",
+	"three":                       "3️⃣",
+	"four":                        "4️⃣",
+	"five":                        "5️⃣",
+	"six":                         "6️⃣",
+	"seven":                       "7️⃣",
+	"eight":                       "8️⃣",
+	"nine":                        "9️⃣",
+	"keycap_ten":                  "🔟",
+}
+
+// Lookup returns the emoji for a shortcode, with or without the colons.
+func Lookup(code string) (string, bool) {
+	e, ok := shortcodes[strings.ToLower(strings.Trim(code, ":"))]
+	return e, ok
+}
+
+// Resolve turns a :shortcode: into its emoji and returns anything else, such
+// as an emoji typed directly, unchanged.
+func Resolve(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 3 || !strings.HasPrefix(s, ":") || !strings.HasSuffix(s, ":") {
+		return s, nil
+	}
+	if e, ok := Lookup(s); ok {
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown emoji shortcode %s", s)
+}
+
+// Shortcode returns a shortcode for an emoji, preferring the shortest name
+// when there are several, or "" if it has none.
+func Shortcode(e string) string {
+	e = Normalize(e)
+	best := ""
+	for code, v := range shortcodes {
+		if Normalize(v) != e {
+			continue
+		}
+		if best == "" || len(code) < len(best) || (len(code) == len(best) && code < best) {
+			best = code
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return ":" + best + ":"
+}
+
+// Normalize strips variation selectors so that, for example, "❤" and "❤️"
+// compare equal.
+func Normalize(e string) string {
+	return strings.ReplaceAll(e, "\uFE0F", "")
+}
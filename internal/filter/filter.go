@@ -0,0 +1,271 @@
+// Package filter implements the small expression language used to select
+// objects for bulk operations, e.g.
+//
+//	last_updated < 90d && comment_count == 0
+//	title ~ "draft" || (!pinned && author == "Jane Doe")
+//
+// Comparisons are ==, !=, <, <=, >, >=, ~ (contains, ignoring case) and !~.
+// They combine with && (or "and"), || (or "or"), ! (or "not") and
+// parentheses. A bare field name tests a boolean field.
+//
+// Literals are numbers, true/false, quoted or bare strings, dates
+// (YYYY-MM-DD) and durations such as 90d, 12h, 2w or 1y. A duration compared
+// with a timestamp field stands for that long before now, so
+// "last_updated < 90d" matches objects last updated more than 90 days ago.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record holds the fields of one object. Values are int, int64, float64,
+// string, bool or time.Time.
+type Record map[string]interface{}
+
+type Filter struct {
+	root node
+	now  time.Time
+}
+
+type node interface {
+	eval(f *Filter, r Record) (bool, error)
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ expr node }
+type fieldNode struct{ field string }
+type compareNode struct {
+	field string
+	op    string
+	value literal
+}
+
+type literal struct {
+	text     string
+	quoted   bool
+	duration time.Duration
+	isDur    bool
+}
+
+// Parse compiles an expression. Durations are resolved against the time of
+// parsing.
+func Parse(expr string) (*Filter, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return &Filter{root: root, now: time.Now()}, nil
+}
+
+// Check reports the first field the filter uses that fields lacks, or a bare
+// field that is not a boolean there. Match only looks at the fields it needs
+// to decide, so checking up front catches typos on either side of && and ||.
+func (f *Filter) Check(fields Record) error {
+	return check(f.root, fields)
+}
+
+func check(n node, fields Record) error {
+	switch n := n.(type) {
+	case andNode:
+		if err := check(n.left, fields); err != nil {
+			return err
+		}
+		return check(n.right, fields)
+	case orNode:
+		if err := check(n.left, fields); err != nil {
+			return err
+		}
+		return check(n.right, fields)
+	case notNode:
+		return check(n.expr, fields)
+	case fieldNode:
+		v, ok := fields[n.field]
+		if !ok {
+			return fmt.Errorf("unknown field %q", n.field)
+		}
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("field %q is not a boolean; compare it with a value", n.field)
+		}
+	case compareNode:
+		if _, ok := fields[n.field]; !ok {
+			return fmt.Errorf("unknown field %q", n.field)
+		}
+	}
+	return nil
+}
+
+// Match reports whether r satisfies the filter.
+func (f *Filter) Match(r Record) (bool, error) {
+	return f.root.eval(f, r)
+}
+
+func (n andNode) eval(f *Filter, r Record) (bool, error) {
+	ok, err := n.left.eval(f, r)
+	if err != nil || !ok {
+		return false, err
+	}
+	return n.right.eval(f, r)
+}
+
+func (n orNode) eval(f *Filter, r Record) (bool, error) {
+	ok, err := n.left.eval(f, r)
+	if err != nil || ok {
+		return ok, err
+	}
+	return n.right.eval(f, r)
+}
+
+func (n notNode) eval(f *Filter, r Record) (bool, error) {
+	ok, err := n.expr.eval(f, r)
+	return !ok, err
+}
+
+func (n fieldNode) eval(f *Filter, r Record) (bool, error) {
+	v, ok := r[n.field]
+	if !ok {
+		return false, fmt.Errorf("unknown field %q", n.field)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("field %q is not a boolean; compare it with a value", n.field)
+	}
+	return b, nil
+}
+
+func (n compareNode) eval(f *Filter, r Record) (bool, error) {
+	v, ok := r[n.field]
+	if !ok {
+		return false, fmt.Errorf("unknown field %q", n.field)
+	}
+
+	if n.op == "~" || n.op == "!~" {
+		contains := strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(n.value.text))
+		return contains == (n.op == "~"), nil
+	}
+
+	var cmp int
+	switch v := v.(type) {
+	case bool:
+		b, err := strconv.ParseBool(n.value.text)
+		if err != nil || n.value.quoted {
+			return false, fmt.Errorf("field %q needs true or false, not %q", n.field, n.value.text)
+		}
+		if n.op != "==" && n.op != "!=" {
+			return false, fmt.Errorf("field %q only supports == and !=", n.field)
+		}
+		return (v == b) == (n.op == "=="), nil
+	case string:
+		cmp = strings.Compare(strings.ToLower(v), strings.ToLower(n.value.text))
+	case int:
+		x, err := n.value.number(n.field)
+		if err != nil {
+			return false, err
+		}
+		cmp = compareFloat(float64(v), x)
+	case int64:
+		x, err := n.value.number(n.field)
+		if err != nil {
+			return false, err
+		}
+		cmp = compareFloat(float64(v), x)
+	case float64:
+		x, err := n.value.number(n.field)
+		if err != nil {
+			return false, err
+		}
+		cmp = compareFloat(v, x)
+	case time.Time:
+		t, err := n.value.time(n.field, f.now)
+		if err != nil {
+			return false, err
+		}
+		cmp = v.Compare(t)
+	default:
+		return false, fmt.Errorf("field %q cannot be compared", n.field)
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func (l literal) number(field string) (float64, error) {
+	x, err := strconv.ParseFloat(l.text, 64)
+	if err != nil || l.quoted {
+		return 0, fmt.Errorf("field %q needs a number, not %q", field, l.text)
+	}
+	return x, nil
+}
+
+func (l literal) time(field string, now time.Time) (time.Time, error) {
+	if l.isDur {
+		return now.Add(-l.duration), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", l.text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("field %q needs a date (YYYY-MM-DD) or a duration such as 30d, not %q", field, l.text)
+	}
+	return t, nil
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseDuration accepts Go durations plus d (days), w (weeks) and y (365
+// days).
+func parseDuration(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	unit := s[len(s)-1]
+	if unit == 'd' || unit == 'w' || unit == 'y' {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return 0, false
+		}
+		d := time.Duration(n) * 24 * time.Hour
+		switch unit {
+		case 'w':
+			d *= 7
+		case 'y':
+			d *= 365
+		}
+		return d, true
+	}
+	if s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil
+}
@@ -0,0 +1,187 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "~", "!", "(", ")"}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		if unicode.IsSpace(c) {
+			i++
+			continue
+		}
+
+		if c == '"' || c == '\'' {
+			end := strings.IndexRune(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string starting at %q", expr[i:])
+			}
+			tokens = append(tokens, token{text: expr[i+1 : i+1+end], quoted: true})
+			i += end + 2
+			continue
+		}
+
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(expr[i:], op) {
+				tokens = append(tokens, token{text: op})
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		start := i
+		for i < len(expr) && !unicode.IsSpace(rune(expr[i])) && !strings.ContainsRune("&|=!<>~()\"'", rune(expr[i])) {
+			i++
+		}
+		if start == i {
+			return nil, fmt.Errorf("unexpected %q", expr[i:])
+		}
+		tokens = append(tokens, token{text: expr[start:i]})
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword reports whether the next token is one of words, unquoted and
+// ignoring case, and consumes it if so.
+func (p *parser) keyword(words ...string) bool {
+	t, ok := p.peek()
+	if !ok || t.quoted {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.keyword("!", "not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	if !t.quoted && t.text == "(" {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return expr, nil
+	}
+
+	if t.quoted || isOperator(t.text) {
+		return nil, fmt.Errorf("expected a field name, got %q", t.text)
+	}
+	field := strings.ToLower(t.text)
+	p.pos++
+
+	op, ok := p.peek()
+	if !ok || op.quoted || !isComparison(op.text) {
+		return fieldNode{field}, nil
+	}
+	p.pos++
+
+	v, ok := p.peek()
+	if !ok || (!v.quoted && isOperator(v.text)) {
+		return nil, fmt.Errorf("expected a value after %s %s", field, op.text)
+	}
+	p.pos++
+
+	lit := literal{text: v.text, quoted: v.quoted}
+	if !v.quoted {
+		lit.duration, lit.isDur = parseDuration(v.text)
+	}
+	return compareNode{field: field, op: op.text, value: lit}, nil
+}
+
+func isOperator(s string) bool {
+	for _, op := range operators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+func isComparison(s string) bool {
+	switch s {
+	case "==", "!=", "<", "<=", ">", ">=", "~", "!~":
+		return true
+	}
+	return false
+}
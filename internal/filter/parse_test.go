@@ -0,0 +1,248 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want node
+	}{
+		{"pinned", fieldNode{"pinned"}},
+		{"Pinned", fieldNode{"pinned"}},
+		{"comment_count == 0", compareNode{field: "comment_count", op: "==", value: literal{text: "0"}}},
+		{"comment_count>=10", compareNode{field: "comment_count", op: ">=", value: literal{text: "10"}}},
+		{`title ~ "draft plan"`, compareNode{field: "title", op: "~", value: literal{text: "draft plan", quoted: true}}},
+		{`author != 'Jane "JD" Doe'`, compareNode{field: "author", op: "!=", value: literal{text: `Jane "JD" Doe`, quoted: true}}},
+		{"title !~ wip", compareNode{field: "title", op: "!~", value: literal{text: "wip"}}},
+		{"last_updated < 90d", compareNode{field: "last_updated", op: "<", value: literal{text: "90d", duration: 90 * 24 * time.Hour, isDur: true}}},
+		// A quoted duration is a string, not a duration.
+		{`title == "90d"`, compareNode{field: "title", op: "==", value: literal{text: "90d", quoted: true}}},
+		{"posted > 2024-01-31", compareNode{field: "posted", op: ">", value: literal{text: "2024-01-31"}}},
+		{"!pinned", notNode{fieldNode{"pinned"}}},
+		{"not not pinned", notNode{notNode{fieldNode{"pinned"}}}},
+		// && binds tighter than ||, and both associate to the left.
+		{"a || b && c", orNode{fieldNode{"a"}, andNode{fieldNode{"b"}, fieldNode{"c"}}}},
+		{"a && b || c", orNode{andNode{fieldNode{"a"}, fieldNode{"b"}}, fieldNode{"c"}}},
+		{"a and b and c", andNode{andNode{fieldNode{"a"}, fieldNode{"b"}}, fieldNode{"c"}}},
+		{"a OR b or c", orNode{orNode{fieldNode{"a"}, fieldNode{"b"}}, fieldNode{"c"}}},
+		{"(a || b) && c", andNode{orNode{fieldNode{"a"}, fieldNode{"b"}}, fieldNode{"c"}}},
+		{"!(a || b)", notNode{orNode{fieldNode{"a"}, fieldNode{"b"}}}},
+		{"!a && b", andNode{notNode{fieldNode{"a"}}, fieldNode{"b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(f.root, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.expr, f.root, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "unexpected end"},
+		{"pinned &&", "unexpected end"},
+		{"!", "unexpected end"},
+		{`title ~ "draft`, "unterminated string"},
+		{"title ~ 'draft", "unterminated string"},
+		{"(pinned", "missing )"},
+		{"pinned)", `unexpected ")"`},
+		{"pinned archived", `unexpected "archived"`},
+		{"title ==", "expected a value after title =="},
+		{"title == && pinned", "expected a value after title =="},
+		{`"title" == x`, `expected a field name, got "title"`},
+		{"== 3", `expected a field name, got "=="`},
+		{"a & b", `unexpected "& b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error containing %q", tt.expr, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"90d", 90 * day, true},
+		{"2w", 14 * day, true},
+		{"1y", 365 * day, true},
+		{"0d", 0, true},
+		{"12h", 12 * time.Hour, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"45m", 45 * time.Minute, true},
+		{"d", 0, false},
+		{"5", 0, false},
+		{"xd", 0, false},
+		{"1.5d", 0, false},
+		{"draft", 0, false},
+		{"2024-01-31", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := parseDuration(tt.in)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseDuration(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Now()
+	r := Record{
+		"title":         "Draft: Q3 plan",
+		"author":        "Jane Doe",
+		"comment_count": 0,
+		"channel_id":    int64(42),
+		"score":         1.5,
+		"pinned":        true,
+		"archived":      false,
+		"last_updated":  now.Add(-100 * 24 * time.Hour),
+		"posted":        time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"pinned", true},
+		{"archived", false},
+		{"!archived && pinned", true},
+		{"comment_count == 0", true},
+		{"comment_count > 0", false},
+		{"channel_id == 42", true},
+		{"score >= 1.5", true},
+		{"score < 1", false},
+		{`title ~ "draft"`, true},
+		{"title ~ DRAFT", true},
+		{"title !~ draft", false},
+		{`author == "jane doe"`, true},
+		{`author != "Jane Doe"`, false},
+		{"last_updated < 90d", true},
+		{"last_updated < 20w", false},
+		{"last_updated < 1y", false},
+		{"posted > 2024-01-01 && posted < 2024-02-01", true},
+		{"pinned == true", true},
+		{"archived != false", false},
+		{"archived || comment_count == 0", true},
+		{"archived && missing_field", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got, err := f.Match(r)
+			if err != nil {
+				t.Fatalf("Match(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	r := Record{
+		"title":         "Plan",
+		"comment_count": 3,
+		"pinned":        true,
+		"last_updated":  time.Now(),
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"missing", `unknown field "missing"`},
+		{"missing == 1", `unknown field "missing"`},
+		{"title", "is not a boolean"},
+		{"comment_count == many", "needs a number"},
+		{`comment_count == "3"`, "needs a number"},
+		{"pinned == yes", "needs true or false"},
+		{"pinned < true", "only supports == and !="},
+		{"last_updated < soon", "needs a date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			_, err = f.Match(r)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Match(%q) error = %v, want it to contain %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	fields := Record{
+		"title":        "",
+		"pinned":       false,
+		"last_updated": time.Time{},
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"pinned", ""},
+		{"!pinned && title ~ x || last_updated < 90d", ""},
+		// The left side decides these, so Match would never see the typo.
+		{"pinned || titel ~ x", `unknown field "titel"`},
+		{"!pinned && (title ~ x || pined)", `unknown field "pined"`},
+		{"not archived", `unknown field "archived"`},
+		{"title", "is not a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			err = f.Check(fields)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Check(%q) = %v, want nil", tt.expr, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Check(%q) = %v, want error containing %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+type Options struct {
+	WorkspaceID    int
+	Source         string
+	UserMap        map[string]int
+	CheckpointPath string
+	DryRun         bool
+	Attribution    bool
+	Log            io.Writer
+}
+
+type Stats struct {
+	ChannelsCreated int
+	ThreadsCreated  int
+	CommentsCreated int
+	Skipped         int
+}
+
+// Checkpoint records the import of one source into one workspace. Items maps
+// source keys ("channel:<key>", "thread:<key>", "comment:<key>") to the IDs
+// of the Twist objects created for them.
+type Checkpoint struct {
+	WorkspaceID int            `json:"workspace_id"`
+	Source      string         `json:"source"`
+	Items       map[string]int `json:"items"`
+}
+
+// LoadCheckpoint reads the checkpoint at path, or starts a new one if there
+// is none. A checkpoint written for another workspace or source is refused,
+// since resuming from it would skip everything.
+func LoadCheckpoint(path string, workspaceID int, source string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Checkpoint{WorkspaceID: workspaceID, Source: source, Items: make(map[string]int)}, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if cp.WorkspaceID != workspaceID || cp.Source != source {
+		return nil, fmt.Errorf("checkpoint %s belongs to an import of %q into workspace %d; use another --checkpoint",
+			path, cp.Source, cp.WorkspaceID)
+	}
+	if cp.Items == nil {
+		cp.Items = make(map[string]int)
+	}
+	return &cp, nil
+}
+
+func (cp *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadUserMap reads a JSON object mapping source user keys (Slack user IDs,
+// backup user IDs or email addresses) to Twist user IDs.
+func LoadUserMap(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user map: %w", err)
+	}
+
+	userMap := make(map[string]int)
+	if err := json.Unmarshal(data, &userMap); err != nil {
+		return nil, fmt.Errorf("failed to parse user map: %w", err)
+	}
+	return userMap, nil
+}
+
+type importer struct {
+	client     *api.Client
+	opts       Options
+	checkpoint *Checkpoint
+	users      map[string]User
+	byEmail    map[string]int
+	stats      *Stats
+	log        io.Writer
+}
+
+func Run(client *api.Client, src *Source, opts Options) (*Stats, error) {
+	im := &importer{
+		client:  client,
+		opts:    opts,
+		users:   make(map[string]User),
+		byEmail: make(map[string]int),
+		stats:   &Stats{},
+		log:     opts.Log,
+	}
+	if im.log == nil {
+		im.log = io.Discard
+	}
+
+	var err error
+	im.checkpoint, err = LoadCheckpoint(opts.CheckpointPath, opts.WorkspaceID, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range src.Users {
+		im.users[u.Key] = u
+	}
+
+	targetUsers, err := client.GetWorkspaceUsers(opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace users: %w", err)
+	}
+	for _, u := range targetUsers {
+		if u.Email != "" {
+			im.byEmail[strings.ToLower(u.Email)] = u.ID
+		}
+	}
+
+	for _, ch := range src.Channels {
+		if err := im.importChannel(ch); err != nil {
+			return im.stats, err
+		}
+	}
+
+	return im.stats, nil
+}
+
+func (im *importer) importChannel(ch Channel) error {
+	key := "channel:" + ch.Key
+	channelID, done := im.checkpoint.Items[key]
+	if done {
+		im.stats.Skipped++
+	} else {
+		opts := make(map[string]interface{})
+		if ch.Description != "" {
+			opts["description"] = ch.Description
+		}
+		var userIDs []int
+		seen := make(map[int]bool)
+		for _, m := range ch.Members {
+			if id, ok := im.twistUser(m); ok && !seen[id] {
+				seen[id] = true
+				userIDs = append(userIDs, id)
+			}
+		}
+		if len(userIDs) > 0 {
+			opts["user_ids"] = userIDs
+		}
+
+		if im.opts.DryRun {
+			fmt.Fprintf(im.log, "Would create channel %q with %d member(s)\n", ch.Name, len(userIDs))
+		} else {
+			channel, err := im.client.CreateChannel(im.opts.WorkspaceID, ch.Name, opts)
+			if err != nil {
+				return fmt.Errorf("failed to create channel %q: %w", ch.Name, err)
+			}
+			channelID = channel.ID
+			if err := im.record(key, channelID); err != nil {
+				return err
+			}
+			fmt.Fprintf(im.log, "Created channel #%d %s\n", channelID, ch.Name)
+		}
+		im.stats.ChannelsCreated++
+	}
+
+	for _, t := range ch.Threads {
+		if err := im.importThread(channelID, ch.Name, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *importer) importThread(channelID int, channelName string, t Thread) error {
+	key := "thread:" + t.Key
+	threadID, done := im.checkpoint.Items[key]
+	if done {
+		im.stats.Skipped++
+	} else {
+		content := im.attribute(t.Author, t.PostedTS, t.Content)
+		if im.opts.DryRun {
+			fmt.Fprintf(im.log, "Would create thread %q in %s (%d replies)\n", t.Title, channelName, len(t.Comments))
+		} else {
+			thread, err := im.client.CreateThread(channelID, t.Title, content, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create thread %q: %w", t.Title, err)
+			}
+			threadID = thread.ID
+			if err := im.record(key, threadID); err != nil {
+				return err
+			}
+			fmt.Fprintf(im.log, "  Created thread #%d %s\n", threadID, t.Title)
+		}
+		im.stats.ThreadsCreated++
+	}
+
+	for _, c := range t.Comments {
+		ckey := "comment:" + c.Key
+		if _, done := im.checkpoint.Items[ckey]; done {
+			im.stats.Skipped++
+			continue
+		}
+		if !im.opts.DryRun {
+			content := im.attribute(c.Author, c.PostedTS, c.Content)
+			comment, err := im.client.PostComment(threadID, content, nil)
+			if err != nil {
+				return fmt.Errorf("failed to post comment to thread %d: %w", threadID, err)
+			}
+			if err := im.record(ckey, comment.ID); err != nil {
+				return err
+			}
+		}
+		im.stats.CommentsCreated++
+	}
+	return nil
+}
+
+func (im *importer) record(key string, id int) error {
+	im.checkpoint.Items[key] = id
+	return im.checkpoint.Save(im.opts.CheckpointPath)
+}
+
+func (im *importer) twistUser(key string) (int, bool) {
+	if id, ok := im.opts.UserMap[key]; ok {
+		return id, true
+	}
+	u, ok := im.users[key]
+	if !ok || u.Email == "" {
+		return 0, false
+	}
+	email := strings.ToLower(u.Email)
+	if id, ok := im.opts.UserMap[email]; ok {
+		return id, true
+	}
+	id, ok := im.byEmail[email]
+	return id, ok
+}
+
+// attribute prefixes imported content with the original author and time,
+// since everything is posted as the user owning the API token.
+func (im *importer) attribute(author string, postedTS int64, content string) string {
+	if !im.opts.Attribution {
+		return content
+	}
+	name := author
+	if u, ok := im.users[author]; ok && u.Name != "" {
+		name = u.Name
+	}
+	if name == "" {
+		name = "unknown user"
+	}
+	posted := time.Unix(postedTS, 0).Format("2006-01-02 15:04")
+	return fmt.Sprintf("_Originally posted by %s on %s_\n\n%s", name, posted, content)
+}
@@ -0,0 +1,254 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type slackChannel struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Purpose struct {
+		Value string `json:"value"`
+	} `json:"purpose"`
+}
+
+type slackUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Profile  struct {
+		Email       string `json:"email"`
+		DisplayName string `json:"display_name"`
+	} `json:"profile"`
+}
+
+type slackMessage struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	User     string `json:"user"`
+	Username string `json:"username"`
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// Membership and rename notices carry no content worth importing.
+var skippedSubtypes = map[string]bool{
+	"channel_join":    true,
+	"channel_leave":   true,
+	"channel_purpose": true,
+	"channel_topic":   true,
+	"channel_name":    true,
+	"channel_archive": true,
+	"group_join":      true,
+	"group_leave":     true,
+	"group_purpose":   true,
+	"group_topic":     true,
+	"group_name":      true,
+}
+
+// ReadSlackExport reads a Slack workspace export zip. Public channels come
+// from channels.json and private channels from groups.json; direct messages
+// are not imported.
+func ReadSlackExport(zipPath string) (*Source, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Slack export: %w", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var users []slackUser
+	if err := readZipJSON(files, "users.json", &users); err != nil {
+		return nil, err
+	}
+
+	var channels []slackChannel
+	if err := readZipJSON(files, "channels.json", &channels); err != nil {
+		return nil, err
+	}
+	if _, ok := files["groups.json"]; ok {
+		var groups []slackChannel
+		if err := readZipJSON(files, "groups.json", &groups); err != nil {
+			return nil, err
+		}
+		channels = append(channels, groups...)
+	}
+
+	src := &Source{}
+	names := make(map[string]string)
+	for _, u := range users {
+		name := u.RealName
+		if name == "" {
+			name = u.Profile.DisplayName
+		}
+		if name == "" {
+			name = u.Name
+		}
+		names[u.ID] = name
+		src.Users = append(src.Users, User{Key: u.ID, Name: name, Email: u.Profile.Email})
+	}
+
+	for _, ch := range channels {
+		var dayFiles []*zip.File
+		for name, f := range files {
+			if path.Dir(name) == ch.Name && strings.HasSuffix(name, ".json") {
+				dayFiles = append(dayFiles, f)
+			}
+		}
+		sort.Slice(dayFiles, func(i, j int) bool { return dayFiles[i].Name < dayFiles[j].Name })
+
+		var messages []slackMessage
+		for _, f := range dayFiles {
+			var day []slackMessage
+			if err := decodeZipFile(f, &day); err != nil {
+				return nil, err
+			}
+			messages = append(messages, day...)
+		}
+
+		src.Channels = append(src.Channels, Channel{
+			Key:         ch.ID,
+			Name:        ch.Name,
+			Description: ch.Purpose.Value,
+			Members:     ch.Members,
+			Threads:     slackThreads(ch.ID, messages, names),
+		})
+	}
+
+	return src, nil
+}
+
+// Slack timestamps are only unique within a channel, so keys are prefixed
+// with the channel ID.
+func slackThreads(channelID string, messages []slackMessage, names map[string]string) []Thread {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return parseSlackTS(messages[i].TS) < parseSlackTS(messages[j].TS)
+	})
+
+	var threads []Thread
+	index := make(map[string]int)
+	for _, m := range messages {
+		if m.Type != "message" || skippedSubtypes[m.Subtype] {
+			continue
+		}
+
+		content := convertSlackText(m.Text, names)
+		author := m.User
+		if author == "" {
+			author = m.Username
+		}
+
+		if m.ThreadTS != "" && m.ThreadTS != m.TS {
+			if i, ok := index[m.ThreadTS]; ok {
+				threads[i].Comments = append(threads[i].Comments, Comment{
+					Key:      channelID + "/" + m.TS,
+					Content:  content,
+					Author:   author,
+					PostedTS: parseSlackTS(m.TS),
+				})
+				continue
+			}
+		}
+
+		index[m.TS] = len(threads)
+		threads = append(threads, Thread{
+			Key:      channelID + "/" + m.TS,
+			Title:    threadTitle(content),
+			Content:  content,
+			Author:   author,
+			PostedTS: parseSlackTS(m.TS),
+		})
+	}
+
+	return threads
+}
+
+var (
+	slackUserRef    = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+	slackChannelRef = regexp.MustCompile(`<#[A-Z0-9]+\|([^>]*)>`)
+	slackSpecialRef = regexp.MustCompile(`<!(here|channel|everyone)(?:\|[^>]*)?>`)
+	slackLinkRef    = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]*))?>`)
+)
+
+func convertSlackText(text string, names map[string]string) string {
+	text = slackUserRef.ReplaceAllStringFunc(text, func(s string) string {
+		id := slackUserRef.FindStringSubmatch(s)[1]
+		if name, ok := names[id]; ok {
+			return "@" + name
+		}
+		return "@" + id
+	})
+	text = slackChannelRef.ReplaceAllString(text, "#$1")
+	text = slackSpecialRef.ReplaceAllString(text, "@$1")
+	text = slackLinkRef.ReplaceAllStringFunc(text, func(s string) string {
+		m := slackLinkRef.FindStringSubmatch(s)
+		if m[2] == "" {
+			return m[1]
+		}
+		return "[" + m[2] + "](" + m[1] + ")"
+	})
+
+	replacer := strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+	return replacer.Replace(text)
+}
+
+func threadTitle(content string) string {
+	title := strings.TrimSpace(content)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if runes := []rune(title); len(runes) > 80 {
+		title = string(runes[:77]) + "..."
+	}
+	if title == "" {
+		title = "Untitled"
+	}
+	return title
+}
+
+func parseSlackTS(ts string) int64 {
+	if i := strings.IndexByte(ts, '.'); i >= 0 {
+		ts = ts[:i]
+	}
+	n, _ := strconv.ParseInt(ts, 10, 64)
+	return n
+}
+
+func readZipJSON(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("Slack export is missing %s", name)
+	}
+	return decodeZipFile(f, v)
+}
+
+func decodeZipFile(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return nil
+}
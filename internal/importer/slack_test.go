@@ -0,0 +1,247 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSlackThreads(t *testing.T) {
+	names := map[string]string{"U1": "Jane Doe"}
+	tests := []struct {
+		name     string
+		messages []slackMessage
+		want     []Thread
+	}{
+		{
+			name: "top-level messages become threads",
+			messages: []slackMessage{
+				{Type: "message", User: "U1", Text: "first", TS: "100.000100"},
+				{Type: "message", User: "U2", Text: "second", TS: "200.000100"},
+			},
+			want: []Thread{
+				{Key: "C1/100.000100", Title: "first", Content: "first", Author: "U1", PostedTS: 100},
+				{Key: "C1/200.000100", Title: "second", Content: "second", Author: "U2", PostedTS: 200},
+			},
+		},
+		{
+			name: "replies are grouped under their thread_ts",
+			messages: []slackMessage{
+				{Type: "message", User: "U1", Text: "question", TS: "100.1", ThreadTS: "100.1"},
+				{Type: "message", User: "U2", Text: "unrelated", TS: "150.1"},
+				{Type: "message", User: "U2", Text: "answer", TS: "200.1", ThreadTS: "100.1"},
+				{Type: "message", User: "U1", Text: "thanks", TS: "300.1", ThreadTS: "100.1"},
+			},
+			want: []Thread{
+				{Key: "C1/100.1", Title: "question", Content: "question", Author: "U1", PostedTS: 100, Comments: []Comment{
+					{Key: "C1/200.1", Content: "answer", Author: "U2", PostedTS: 200},
+					{Key: "C1/300.1", Content: "thanks", Author: "U1", PostedTS: 300},
+				}},
+				{Key: "C1/150.1", Title: "unrelated", Content: "unrelated", Author: "U2", PostedTS: 150},
+			},
+		},
+		{
+			name: "messages are ordered by timestamp before grouping",
+			messages: []slackMessage{
+				{Type: "message", User: "U2", Text: "answer", TS: "200.1", ThreadTS: "100.1"},
+				{Type: "message", User: "U1", Text: "question", TS: "100.1", ThreadTS: "100.1"},
+			},
+			want: []Thread{
+				{Key: "C1/100.1", Title: "question", Content: "question", Author: "U1", PostedTS: 100, Comments: []Comment{
+					{Key: "C1/200.1", Content: "answer", Author: "U2", PostedTS: 200},
+				}},
+			},
+		},
+		{
+			name: "replies to a missing parent start their own thread",
+			messages: []slackMessage{
+				{Type: "message", User: "U2", Text: "orphan", TS: "200.1", ThreadTS: "100.1"},
+			},
+			want: []Thread{
+				{Key: "C1/200.1", Title: "orphan", Content: "orphan", Author: "U2", PostedTS: 200},
+			},
+		},
+		{
+			name: "notices and non-messages are skipped",
+			messages: []slackMessage{
+				{Type: "message", Subtype: "channel_join", User: "U1", Text: "<@U1> has joined", TS: "100.1"},
+				{Type: "message", Subtype: "channel_topic", User: "U1", Text: "set the topic", TS: "110.1"},
+				{Type: "reaction_added", User: "U1", TS: "120.1"},
+				{Type: "message", Subtype: "bot_message", Username: "deploybot", Text: "deployed", TS: "130.1"},
+			},
+			want: []Thread{
+				{Key: "C1/130.1", Title: "deployed", Content: "deployed", Author: "deploybot", PostedTS: 130},
+			},
+		},
+		{
+			name: "text is converted",
+			messages: []slackMessage{
+				{Type: "message", User: "U1", Text: "hi <@U1> &amp; <!here>\nsecond line", TS: "100.1"},
+			},
+			want: []Thread{
+				{Key: "C1/100.1", Title: "hi @Jane Doe & @here", Content: "hi @Jane Doe & @here\nsecond line", Author: "U1", PostedTS: 100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slackThreads("C1", tt.messages, names)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slackThreads() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertSlackText(t *testing.T) {
+	names := map[string]string{"U1": "Jane Doe"}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"hi <@U1>", "hi @Jane Doe"},
+		{"hi <@U1|jane>", "hi @Jane Doe"},
+		{"hi <@U9>", "hi @U9"},
+		{"see <#C123|general>", "see #general"},
+		{"<!channel> and <!everyone|everyone>", "@channel and @everyone"},
+		{"<https://example.com>", "https://example.com"},
+		{"<https://example.com|the site>", "[the site](https://example.com)"},
+		{"<mailto:jane@example.com|Jane>", "[Jane](mailto:jane@example.com)"},
+		{"a &lt;b&gt; &amp;&amp; c", "a <b> && c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := convertSlackText(tt.in, names); got != tt.want {
+				t.Errorf("convertSlackText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreadTitle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"short", "short"},
+		{"  padded  \nrest", "padded"},
+		{"", "Untitled"},
+		{"\n\nbody", "body"},
+		{"  \n ", "Untitled"},
+		{strings.Repeat("é", 100), strings.Repeat("é", 77) + "..."},
+	}
+
+	for _, tt := range tests {
+		if got := threadTitle(tt.in); got != tt.want {
+			t.Errorf("threadTitle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSlackTS(t *testing.T) {
+	tests := map[string]int64{
+		"1700000000.123456": 1700000000,
+		"1700000000":        1700000000,
+		"":                  0,
+		"bad":               0,
+	}
+	for in, want := range tests {
+		if got := parseSlackTS(in); got != want {
+			t.Errorf("parseSlackTS(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestReadSlackExport(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "export.zip")
+	writeZip(t, zipPath, map[string]interface{}{
+		"users.json": []map[string]interface{}{
+			{"id": "U1", "name": "jane", "real_name": "Jane Doe", "profile": map[string]string{"email": "jane@example.com"}},
+			{"id": "U2", "name": "bob", "profile": map[string]string{"display_name": "Bobby"}},
+		},
+		"channels.json": []map[string]interface{}{
+			{"id": "C1", "name": "general", "members": []string{"U1", "U2"}, "purpose": map[string]string{"value": "Chat"}},
+		},
+		"groups.json": []map[string]interface{}{
+			{"id": "G1", "name": "secret"},
+		},
+		"general/2024-01-02.json": []slackMessage{
+			{Type: "message", User: "U2", Text: "reply", TS: "1704200000.1", ThreadTS: "1704100000.1"},
+		},
+		"general/2024-01-01.json": []slackMessage{
+			{Type: "message", User: "U1", Text: "hello", TS: "1704100000.1", ThreadTS: "1704100000.1"},
+		},
+		"secret/2024-01-01.json": []slackMessage{
+			{Type: "message", User: "U1", Text: "private", TS: "1704100000.1"},
+		},
+	})
+
+	src, err := ReadSlackExport(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantUsers := []User{
+		{Key: "U1", Name: "Jane Doe", Email: "jane@example.com"},
+		{Key: "U2", Name: "Bobby"},
+	}
+	if !reflect.DeepEqual(src.Users, wantUsers) {
+		t.Errorf("users = %#v, want %#v", src.Users, wantUsers)
+	}
+
+	wantChannels := []Channel{
+		{Key: "C1", Name: "general", Description: "Chat", Members: []string{"U1", "U2"}, Threads: []Thread{
+			{Key: "C1/1704100000.1", Title: "hello", Content: "hello", Author: "U1", PostedTS: 1704100000, Comments: []Comment{
+				{Key: "C1/1704200000.1", Content: "reply", Author: "U2", PostedTS: 1704200000},
+			}},
+		}},
+		{Key: "G1", Name: "secret", Threads: []Thread{
+			{Key: "G1/1704100000.1", Title: "private", Content: "private", Author: "U1", PostedTS: 1704100000},
+		}},
+	}
+	if !reflect.DeepEqual(src.Channels, wantChannels) {
+		t.Errorf("channels =\n%#v\nwant\n%#v", src.Channels, wantChannels)
+	}
+}
+
+func TestReadSlackExportMissingFiles(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "export.zip")
+	writeZip(t, zipPath, map[string]interface{}{
+		"users.json": []map[string]string{},
+	})
+
+	_, err := ReadSlackExport(zipPath)
+	if err == nil || !strings.Contains(err.Error(), "missing channels.json") {
+		t.Errorf("ReadSlackExport() error = %v, want missing channels.json", err)
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]interface{}) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, v := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
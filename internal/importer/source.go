@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"strconv"
+
+	"github.com/intelligrit/twist-cli/internal/backup"
+)
+
+// Source is the normalized content read from a backup directory or a Slack
+// export. Keys are the identifiers used by the source system and are what
+// the checkpoint file and user mapping file refer to.
+type Source struct {
+	Users    []User
+	Channels []Channel
+}
+
+type User struct {
+	Key   string
+	Name  string
+	Email string
+}
+
+type Channel struct {
+	Key         string
+	Name        string
+	Description string
+	Members     []string
+	Threads     []Thread
+}
+
+type Thread struct {
+	Key      string
+	Title    string
+	Content  string
+	Author   string
+	PostedTS int64
+	Comments []Comment
+}
+
+type Comment struct {
+	Key      string
+	Content  string
+	Author   string
+	PostedTS int64
+}
+
+func ReadBackup(dir string) (*Source, error) {
+	if _, err := backup.ReadManifest(dir); err != nil {
+		return nil, err
+	}
+
+	src := &Source{}
+
+	users, err := backup.ReadUsers(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		src.Users = append(src.Users, User{Key: strconv.Itoa(u.ID), Name: u.Name, Email: u.Email})
+	}
+
+	channels, err := backup.ReadChannels(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range channels {
+		records, err := backup.ReadThreads(dir, ch.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		channel := Channel{
+			Key:         strconv.Itoa(ch.ID),
+			Name:        ch.Name,
+			Description: ch.Description,
+		}
+		members := make(map[string]bool)
+		for _, r := range records {
+			thread := Thread{
+				Key:      strconv.Itoa(r.Thread.ID),
+				Title:    r.Thread.Title,
+				Content:  r.Thread.Content,
+				Author:   strconv.Itoa(r.Thread.Creator),
+				PostedTS: r.Thread.PostedTS,
+			}
+			members[thread.Author] = true
+			for _, c := range r.Comments {
+				thread.Comments = append(thread.Comments, Comment{
+					Key:      strconv.Itoa(c.Comment.ID),
+					Content:  c.Comment.Content,
+					Author:   strconv.Itoa(c.Comment.Creator),
+					PostedTS: c.Comment.PostedTS,
+				})
+				members[strconv.Itoa(c.Comment.Creator)] = true
+			}
+			channel.Threads = append(channel.Threads, thread)
+		}
+		for key := range members {
+			channel.Members = append(channel.Members, key)
+		}
+
+		src.Channels = append(src.Channels, channel)
+	}
+
+	return src, nil
+}
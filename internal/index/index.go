@@ -0,0 +1,282 @@
+package index
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type Document struct {
+	Type           string
+	ID             int
+	Title          string
+	ThreadTitle    string
+	Content        string
+	Author         string
+	AuthorID       int
+	Channel        string
+	ChannelID      int
+	ThreadID       int
+	ConversationID int
+	PostedTS       int64
+}
+
+type Posting struct {
+	Doc       int
+	Positions []int
+}
+
+// Index is an inverted index over the title and content of each document.
+// Positions are kept so that phrase queries can be answered.
+type Index struct {
+	Docs     []Document
+	Postings map[string][]Posting
+}
+
+type Result struct {
+	Document Document
+	Score    float64
+	Snippet  string
+}
+
+// titleGap separates the positions of title and content terms so that a
+// phrase cannot match across the two.
+const titleGap = 100
+
+// Build indexes docs in order, so each term's postings are sorted by Doc.
+func Build(docs []Document) *Index {
+	idx := &Index{Docs: docs, Postings: make(map[string][]Posting)}
+	for i, doc := range docs {
+		positions := make(map[string][]int)
+		title := Tokenize(doc.Title)
+		for pos, term := range title {
+			positions[term] = append(positions[term], pos)
+		}
+		for pos, term := range Tokenize(doc.Content) {
+			positions[term] = append(positions[term], len(title)+titleGap+pos)
+		}
+		for term, p := range positions {
+			idx.Postings[term] = append(idx.Postings[term], Posting{Doc: i, Positions: p})
+		}
+	}
+	return idx
+}
+
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var idx Index
+	if err := gob.NewDecoder(f).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	return &idx, nil
+}
+
+func (idx *Index) Save(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := gob.NewEncoder(f).Encode(idx); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Search evaluates a query (see Parse) and returns matches ordered by
+// relevance, most recent first among equal scores.
+func (idx *Index) Search(query string, limit int, hlOpen, hlClose string) ([]Result, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := idx.eval(q)
+	terms := q.terms()
+
+	var results []Result
+	for doc := range matches {
+		results = append(results, Result{
+			Document: idx.Docs[doc],
+			Score:    idx.score(doc, terms),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Document.PostedTS > results[j].Document.PostedTS
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Snippet = Snippet(results[i].Document.Content, terms, hlOpen, hlClose)
+	}
+
+	return results, nil
+}
+
+func (idx *Index) allDocs() map[int]bool {
+	set := make(map[int]bool, len(idx.Docs))
+	for i := range idx.Docs {
+		set[i] = true
+	}
+	return set
+}
+
+func (idx *Index) termDocs(term string) map[int]bool {
+	set := make(map[int]bool)
+	for _, p := range idx.Postings[term] {
+		set[p.Doc] = true
+	}
+	return set
+}
+
+func (idx *Index) phraseDocs(phrase []string) map[int]bool {
+	set := make(map[int]bool)
+	if len(phrase) == 0 {
+		return set
+	}
+
+	positions := make([]map[int][]int, len(phrase))
+	for i, term := range phrase {
+		positions[i] = make(map[int][]int)
+		for _, p := range idx.Postings[term] {
+			positions[i][p.Doc] = p.Positions
+		}
+	}
+
+	for doc, starts := range positions[0] {
+		for _, start := range starts {
+			found := true
+			for i := 1; i < len(phrase); i++ {
+				if !containsInt(positions[i][doc], start+i) {
+					found = false
+					break
+				}
+			}
+			if found {
+				set[doc] = true
+				break
+			}
+		}
+	}
+	return set
+}
+
+// score is a plain tf-idf sum over the free-text terms of the query, with
+// title matches counted twice.
+func (idx *Index) score(doc int, terms []string) float64 {
+	n := float64(len(idx.Docs))
+	titleTerms := make(map[string]bool)
+	for _, t := range Tokenize(idx.Docs[doc].Title) {
+		titleTerms[t] = true
+	}
+
+	score := 0.0
+	for _, term := range terms {
+		postings := idx.Postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + n/float64(len(postings)))
+		if p, ok := findPosting(postings, doc); ok {
+			score += (1 + math.Log(float64(len(p.Positions)))) * idf
+		}
+		if titleTerms[term] {
+			score += idf
+		}
+	}
+	return score
+}
+
+// findPosting looks up doc in postings, which Build keeps sorted by Doc.
+func findPosting(postings []Posting, doc int) (Posting, bool) {
+	i := sort.Search(len(postings), func(i int) bool { return postings[i].Doc >= doc })
+	if i < len(postings) && postings[i].Doc == doc {
+		return postings[i], true
+	}
+	return Posting{}, false
+}
+
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Snippet returns a short window of content around the first query term,
+// with every query term wrapped in hlOpen and hlClose.
+func Snippet(content string, terms []string, hlOpen, hlClose string) string {
+	const window = 12
+
+	want := make(map[string]bool)
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	words := strings.Fields(content)
+	first := -1
+	for i, w := range words {
+		if matchesWord(w, want) {
+			first = i
+			break
+		}
+	}
+
+	start := 0
+	if first > window/2 {
+		start = first - window/2
+	}
+	end := start + window
+	if end > len(words) {
+		end = len(words)
+	}
+
+	parts := make([]string, 0, end-start)
+	for _, w := range words[start:end] {
+		if matchesWord(w, want) {
+			w = hlOpen + w + hlClose
+		}
+		parts = append(parts, w)
+	}
+
+	snippet := strings.Join(parts, " ")
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(words) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+func matchesWord(word string, want map[string]bool) bool {
+	for _, t := range Tokenize(word) {
+		if want[t] {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, v int) bool {
+	i := sort.SearchInts(list, v)
+	return i < len(list) && list[i] == v
+}
@@ -0,0 +1,392 @@
+package index
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query syntax:
+//
+//	deploy failed           both terms (AND is implicit)
+//	deploy OR rollback      either term
+//	NOT flaky, -flaky       exclude a term
+//	"exact phrase"          terms adjacent and in order
+//	(a OR b) c              grouping
+//	author:jane             author name contains "jane" (or author ID)
+//	channel:ops             channel name contains "ops" (or channel ID)
+//	type:thread             thread, comment or message
+//	before:2024-01-31       posted before that day
+//	after:2024-01-01        posted after that day
+type node interface {
+	eval(idx *Index) map[int]bool
+}
+
+type termNode struct{ terms []string }
+type fieldNode struct {
+	field string
+	value string
+}
+type notNode struct{ child node }
+type andNode struct{ children []node }
+type orNode struct{ children []node }
+
+var fields = map[string]bool{
+	"author":  true,
+	"channel": true,
+	"type":    true,
+	"before":  true,
+	"after":   true,
+}
+
+type Query struct {
+	root node
+}
+
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in query", p.tokens[p.pos].text)
+	}
+	if err := validate(root); err != nil {
+		return nil, err
+	}
+	return &Query{root: root}, nil
+}
+
+func (idx *Index) eval(q *Query) map[int]bool {
+	return q.root.eval(idx)
+}
+
+// terms returns the free-text terms that count towards ranking and
+// highlighting; negated terms are left out.
+func (q *Query) terms() []string {
+	var terms []string
+	var walk func(n node)
+	walk = func(n node) {
+		switch n := n.(type) {
+		case termNode:
+			terms = append(terms, n.terms...)
+		case andNode:
+			for _, c := range n.children {
+				walk(c)
+			}
+		case orNode:
+			for _, c := range n.children {
+				walk(c)
+			}
+		}
+	}
+	walk(q.root)
+	return terms
+}
+
+func (n termNode) eval(idx *Index) map[int]bool {
+	if len(n.terms) == 1 {
+		return idx.termDocs(n.terms[0])
+	}
+	return idx.phraseDocs(n.terms)
+}
+
+func (n fieldNode) eval(idx *Index) map[int]bool {
+	set := make(map[int]bool)
+	value := strings.ToLower(n.value)
+	switch n.field {
+	case "channel":
+		value = strings.TrimPrefix(value, "#")
+	case "type":
+		if value == "dm" {
+			value = "message"
+		}
+	}
+	id, idErr := strconv.Atoi(value)
+
+	var day time.Time
+	if n.field == "before" || n.field == "after" {
+		day, _ = time.ParseInLocation("2006-01-02", value, time.Local)
+	}
+
+	for i, doc := range idx.Docs {
+		match := false
+		switch n.field {
+		case "author":
+			match = (idErr == nil && doc.AuthorID == id) ||
+				strings.Contains(strings.ToLower(doc.Author), value)
+		case "channel":
+			match = (idErr == nil && doc.ChannelID == id) ||
+				(doc.Channel != "" && strings.Contains(strings.ToLower(doc.Channel), value))
+		case "type":
+			match = doc.Type == value
+		case "before":
+			match = doc.PostedTS < day.Unix()
+		case "after":
+			match = doc.PostedTS >= day.AddDate(0, 0, 1).Unix()
+		}
+		if match {
+			set[i] = true
+		}
+	}
+	return set
+}
+
+func (n notNode) eval(idx *Index) map[int]bool {
+	exclude := n.child.eval(idx)
+	set := idx.allDocs()
+	for doc := range exclude {
+		delete(set, doc)
+	}
+	return set
+}
+
+func (n andNode) eval(idx *Index) map[int]bool {
+	set := n.children[0].eval(idx)
+	for _, c := range n.children[1:] {
+		other := c.eval(idx)
+		for doc := range set {
+			if !other[doc] {
+				delete(set, doc)
+			}
+		}
+	}
+	return set
+}
+
+func (n orNode) eval(idx *Index) map[int]bool {
+	set := make(map[int]bool)
+	for _, c := range n.children {
+		for doc := range c.eval(idx) {
+			set[doc] = true
+		}
+	}
+	return set
+}
+
+func validate(n node) error {
+	switch n := n.(type) {
+	case fieldNode:
+		if n.field == "before" || n.field == "after" {
+			if _, err := time.Parse("2006-01-02", n.value); err != nil {
+				return fmt.Errorf("invalid date %q for %s (use YYYY-MM-DD)", n.value, n.field)
+			}
+		}
+	case notNode:
+		return validate(n.child)
+	case andNode:
+		for _, c := range n.children {
+			if err := validate(c); err != nil {
+				return err
+			}
+		}
+	case orNode:
+		for _, c := range n.children {
+			if err := validate(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokField
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	field string
+}
+
+func lex(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] != ' ':
+			tokens = append(tokens, token{kind: tokNot, text: "-"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(" \t\n()\"", runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			i = end
+
+			if colon := strings.IndexByte(word, ':'); colon > 0 && fields[strings.ToLower(word[:colon])] {
+				field := strings.ToLower(word[:colon])
+				value := word[colon+1:]
+				if value == "" && i < len(runes) && runes[i] == '"' {
+					end := i + 1
+					for end < len(runes) && runes[end] != '"' {
+						end++
+					}
+					if end >= len(runes) {
+						return nil, fmt.Errorf("unterminated quote in query")
+					}
+					value = string(runes[i+1 : end])
+					i = end + 1
+				}
+				if value == "" {
+					return nil, fmt.Errorf("missing value for %s:", field)
+				}
+				tokens = append(tokens, token{kind: tokField, text: word, field: field + ":" + value})
+				continue
+			}
+
+			switch word {
+			case "AND", "&&":
+				tokens = append(tokens, token{kind: tokAnd, text: word})
+			case "OR", "||":
+				tokens = append(tokens, token{kind: tokOr, text: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokNot, text: word})
+			default:
+				tokens = append(tokens, token{kind: tokWord, text: word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for t := p.peek(); t != nil && t.kind == tokOr; t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return orNode{children: children}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for t := p.peek(); t != nil && t.kind != tokOr && t.kind != tokRParen; t = p.peek() {
+		if t.kind == tokAnd {
+			p.pos++
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return andNode{children: children}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	if t.kind == tokNot {
+		p.pos++
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	p.pos++
+
+	switch t.kind {
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return n, nil
+	case tokWord, tokPhrase:
+		terms := Tokenize(t.text)
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("%q contains no searchable words", t.text)
+		}
+		return termNode{terms: terms}, nil
+	case tokField:
+		parts := strings.SplitN(t.field, ":", 2)
+		return fieldNode{field: parts[0], value: parts[1]}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in query", t.text)
+}
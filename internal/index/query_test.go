@@ -0,0 +1,179 @@
+package index
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  node
+	}{
+		{"deploy", termNode{terms: []string{"deploy"}}},
+		{"Deploy-Failed", termNode{terms: []string{"deploy", "failed"}}},
+		{"deploy failed", andNode{children: []node{
+			termNode{terms: []string{"deploy"}},
+			termNode{terms: []string{"failed"}},
+		}}},
+		{"deploy AND failed", andNode{children: []node{
+			termNode{terms: []string{"deploy"}},
+			termNode{terms: []string{"failed"}},
+		}}},
+		{`"deploy failed"`, termNode{terms: []string{"deploy", "failed"}}},
+		{`"deploy (failed)"`, termNode{terms: []string{"deploy", "failed"}}},
+		// AND binds tighter than OR.
+		{"a b OR c", orNode{children: []node{
+			andNode{children: []node{termNode{terms: []string{"a"}}, termNode{terms: []string{"b"}}}},
+			termNode{terms: []string{"c"}},
+		}}},
+		{"a || b && c", orNode{children: []node{
+			termNode{terms: []string{"a"}},
+			andNode{children: []node{termNode{terms: []string{"b"}}, termNode{terms: []string{"c"}}}},
+		}}},
+		{"(a OR b) c", andNode{children: []node{
+			orNode{children: []node{termNode{terms: []string{"a"}}, termNode{terms: []string{"b"}}}},
+			termNode{terms: []string{"c"}},
+		}}},
+		{"NOT flaky", notNode{child: termNode{terms: []string{"flaky"}}}},
+		{"deploy -flaky", andNode{children: []node{
+			termNode{terms: []string{"deploy"}},
+			notNode{child: termNode{terms: []string{"flaky"}}},
+		}}},
+		{"-(a OR b)", notNode{child: orNode{children: []node{
+			termNode{terms: []string{"a"}},
+			termNode{terms: []string{"b"}},
+		}}}},
+		{"author:jane", fieldNode{field: "author", value: "jane"}},
+		{"Channel:#ops", fieldNode{field: "channel", value: "#ops"}},
+		{`author:"Jane Doe" deploy`, andNode{children: []node{
+			fieldNode{field: "author", value: "Jane Doe"},
+			termNode{terms: []string{"deploy"}},
+		}}},
+		{"after:2024-01-01 before:2024-01-31", andNode{children: []node{
+			fieldNode{field: "after", value: "2024-01-01"},
+			fieldNode{field: "before", value: "2024-01-31"},
+		}}},
+		// Unknown fields are plain words.
+		{"http://example", termNode{terms: []string{"http", "example"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(q.root, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.query, q.root, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "empty query"},
+		{"   ", "empty query"},
+		{`"deploy failed`, "unterminated quote"},
+		{`author:"jane`, "unterminated quote"},
+		{"author:", "missing value"},
+		{"(a OR b", "missing closing parenthesis"},
+		{"a OR", "unexpected end"},
+		{"NOT", "unexpected end"},
+		{"a)", `unexpected ")"`},
+		{"()", `unexpected ")"`},
+		{`"!!"`, "no searchable words"},
+		{"before:yesterday", "invalid date"},
+		{"after:2024-13-01", "invalid date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error containing %q", tt.query, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	day := func(s string) int64 {
+		d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.Add(12 * time.Hour).Unix()
+	}
+	idx := Build([]Document{
+		{Type: "thread", ID: 1, Title: "Deploy failed", Content: "The rollout broke staging", Author: "Jane Doe", AuthorID: 10, Channel: "ops", ChannelID: 100, PostedTS: day("2024-01-10")},
+		{Type: "comment", ID: 2, Content: "deploy worked after a retry", Author: "Bob", AuthorID: 11, Channel: "ops", ChannelID: 100, PostedTS: day("2024-02-01")},
+		{Type: "message", ID: 3, Content: "flaky test in failed deploy", Author: "Jane Doe", AuthorID: 10, PostedTS: day("2024-03-01")},
+		{Type: "thread", ID: 4, Title: "Release deploy", Content: "failed checks", Author: "Bob", AuthorID: 11, Channel: "dev", ChannelID: 200, PostedTS: day("2024-03-05")},
+	})
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"deploy", []int{1, 2, 3, 4}},
+		{"deploy failed", []int{1, 3, 4}},
+		{`"deploy failed"`, []int{1}},
+		{`"failed deploy"`, []int{3}},
+		// The title and content are not one run of text.
+		{`"deploy failed checks"`, nil},
+		{"rollout OR retry", []int{1, 2}},
+		{"deploy -failed", []int{2}},
+		{"deploy NOT (flaky OR retry)", []int{1, 4}},
+		{"author:jane", []int{1, 3}},
+		{"author:11", []int{2, 4}},
+		{"channel:#ops deploy", []int{1, 2}},
+		{"channel:200", []int{4}},
+		{"type:dm", []int{3}},
+		{"type:thread failed", []int{1, 4}},
+		{"before:2024-02-01", []int{1}},
+		{"after:2024-02-01", []int{3, 4}},
+		{"after:2024-01-31 before:2024-03-02", []int{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := idx.Search(tt.query, 0, "", "")
+			if err != nil {
+				t.Fatalf("Search(%q): %v", tt.query, err)
+			}
+			var got []int
+			for _, r := range results {
+				got = append(got, r.Document.ID)
+			}
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRanksTitleMatchesFirst(t *testing.T) {
+	idx := Build([]Document{
+		{ID: 1, Content: "notes about the outage", PostedTS: 2},
+		{ID: 2, Title: "Outage", Content: "notes", PostedTS: 1},
+	})
+	results, err := idx.Search("outage", 0, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Document.ID != 2 {
+		t.Fatalf("Search ranked %v, want document 2 first", results)
+	}
+}
@@ -0,0 +1,88 @@
+package mirror
+
+import (
+	"database/sql"
+
+	"github.com/intelligrit/twist-cli/internal/index"
+)
+
+// Documents returns every mirrored thread, comment and conversation message
+// in the shape used by the search index.
+func (m *Mirror) Documents() ([]index.Document, error) {
+	var docs []index.Document
+
+	rows, err := m.db.Query(`SELECT t.id, t.title, t.content, t.creator, COALESCE(u.name, ''),
+		t.channel_id, COALESCE(c.name, ''), t.posted_ts
+		FROM threads t
+		LEFT JOIN users u ON u.id = t.creator AND u.workspace_id = t.workspace_id
+		LEFT JOIN channels c ON c.id = t.channel_id`)
+	if err != nil {
+		return nil, err
+	}
+	err = collect(rows, func(rows *sql.Rows) error {
+		d := index.Document{Type: "thread"}
+		if err := rows.Scan(&d.ID, &d.Title, &d.Content, &d.AuthorID, &d.Author,
+			&d.ChannelID, &d.Channel, &d.PostedTS); err != nil {
+			return err
+		}
+		d.ThreadID = d.ID
+		d.ThreadTitle = d.Title
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = m.db.Query(`SELECT cm.id, cm.thread_id, COALESCE(t.title, ''), cm.content, cm.creator,
+		COALESCE(u.name, ''), cm.channel_id, COALESCE(c.name, ''), cm.posted_ts
+		FROM comments cm
+		LEFT JOIN threads t ON t.id = cm.thread_id
+		LEFT JOIN users u ON u.id = cm.creator AND u.workspace_id = cm.workspace_id
+		LEFT JOIN channels c ON c.id = cm.channel_id`)
+	if err != nil {
+		return nil, err
+	}
+	err = collect(rows, func(rows *sql.Rows) error {
+		d := index.Document{Type: "comment"}
+		if err := rows.Scan(&d.ID, &d.ThreadID, &d.ThreadTitle, &d.Content, &d.AuthorID, &d.Author,
+			&d.ChannelID, &d.Channel, &d.PostedTS); err != nil {
+			return err
+		}
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = m.db.Query(`SELECT m.id, m.conversation_id, m.content, m.user_id,
+		COALESCE((SELECT name FROM users u WHERE u.id = m.user_id LIMIT 1), ''), m.created_ts
+		FROM messages m`)
+	if err != nil {
+		return nil, err
+	}
+	err = collect(rows, func(rows *sql.Rows) error {
+		d := index.Document{Type: "message"}
+		if err := rows.Scan(&d.ID, &d.ConversationID, &d.Content, &d.AuthorID, &d.Author, &d.PostedTS); err != nil {
+			return err
+		}
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+func collect(rows *sql.Rows, fn func(*sql.Rows) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
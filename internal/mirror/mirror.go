@@ -0,0 +1,264 @@
+package mirror
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/intelligrit/twist-cli/internal/config"
+	"github.com/intelligrit/twist-cli/pkg/api"
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS workspaces (
+	id INTEGER PRIMARY KEY,
+	name TEXT,
+	plan TEXT,
+	created_ts INTEGER
+);
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER,
+	workspace_id INTEGER,
+	name TEXT,
+	email TEXT,
+	user_type TEXT,
+	bot INTEGER,
+	removed INTEGER,
+	PRIMARY KEY (id, workspace_id)
+);
+CREATE TABLE IF NOT EXISTS channels (
+	id INTEGER PRIMARY KEY,
+	workspace_id INTEGER,
+	name TEXT,
+	description TEXT,
+	public INTEGER,
+	archived INTEGER,
+	created_ts INTEGER
+);
+CREATE TABLE IF NOT EXISTS threads (
+	id INTEGER PRIMARY KEY,
+	workspace_id INTEGER,
+	channel_id INTEGER,
+	title TEXT,
+	content TEXT,
+	creator INTEGER,
+	posted_ts INTEGER,
+	last_updated_ts INTEGER,
+	comment_count INTEGER,
+	starred INTEGER,
+	pinned INTEGER,
+	archived INTEGER
+);
+CREATE INDEX IF NOT EXISTS threads_channel ON threads (channel_id);
+CREATE TABLE IF NOT EXISTS comments (
+	id INTEGER PRIMARY KEY,
+	thread_id INTEGER,
+	channel_id INTEGER,
+	workspace_id INTEGER,
+	content TEXT,
+	creator INTEGER,
+	posted_ts INTEGER,
+	last_updated_ts INTEGER
+);
+CREATE INDEX IF NOT EXISTS comments_thread ON comments (thread_id);
+CREATE TABLE IF NOT EXISTS conversations (
+	id INTEGER PRIMARY KEY,
+	workspace_id INTEGER,
+	user_ids TEXT,
+	message_count INTEGER,
+	created_ts INTEGER,
+	last_active_ts INTEGER,
+	archived INTEGER,
+	muted INTEGER
+);
+CREATE TABLE IF NOT EXISTS messages (
+	id INTEGER PRIMARY KEY,
+	conversation_id INTEGER,
+	content TEXT,
+	user_id INTEGER,
+	created_ts INTEGER
+);
+CREATE INDEX IF NOT EXISTS messages_conversation ON messages (conversation_id);
+CREATE TABLE IF NOT EXISTS sync_state (
+	workspace_id INTEGER PRIMARY KEY,
+	last_sync_ts INTEGER
+);
+`
+
+type Mirror struct {
+	db *sql.DB
+}
+
+func DefaultPath() (string, error) {
+	return config.Path("mirror.db")
+}
+
+func Open(path string) (*Mirror, error) {
+	dsn, err := fileURI(path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize mirror: %w", err)
+	}
+	return &Mirror{db: db}, nil
+}
+
+// OpenReadOnly opens an existing mirror so that no statement run through it
+// can change the database, for running queries supplied by the user.
+func OpenReadOnly(path string) (*Mirror, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	dsn, err := fileURI(path, "mode=ro&_pragma=query_only(1)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	return &Mirror{db: db}, nil
+}
+
+// fileURI returns the SQLite URI for the database at path, escaping any
+// characters in the path that a URI would otherwise treat specially.
+func fileURI(path, query string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: abs, RawQuery: query}).String(), nil
+}
+
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+// Query runs an arbitrary query and returns the column names and the
+// rows rendered as strings.
+func (m *Mirror) Query(query string) ([]string, [][]string, error) {
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result [][]string
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, err
+		}
+
+		row := make([]string, len(columns))
+		for i, v := range values {
+			switch v := v.(type) {
+			case nil:
+				row[i] = "NULL"
+			case []byte:
+				row[i] = string(v)
+			default:
+				row[i] = fmt.Sprint(v)
+			}
+		}
+		result = append(result, row)
+	}
+
+	return columns, result, rows.Err()
+}
+
+func (m *Mirror) Threads(channelID int) ([]api.Thread, error) {
+	rows, err := m.db.Query(`SELECT id, workspace_id, channel_id, title, content, creator, posted_ts,
+		last_updated_ts, comment_count, starred, pinned, archived
+		FROM threads WHERE channel_id = ? ORDER BY last_updated_ts DESC`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var threads []api.Thread
+	for rows.Next() {
+		t, err := scanThread(rows)
+		if err != nil {
+			return nil, err
+		}
+		threads = append(threads, *t)
+	}
+	return threads, rows.Err()
+}
+
+func (m *Mirror) Thread(id int) (*api.Thread, error) {
+	row := m.db.QueryRow(`SELECT id, workspace_id, channel_id, title, content, creator, posted_ts,
+		last_updated_ts, comment_count, starred, pinned, archived
+		FROM threads WHERE id = ?`, id)
+	t, err := scanThread(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("thread %d is not in the mirror; run 'twist sync' first", id)
+	}
+	return t, err
+}
+
+func (m *Mirror) Comments(threadID int) ([]api.Comment, error) {
+	rows, err := m.db.Query(`SELECT id, thread_id, content, creator, posted_ts, last_updated_ts
+		FROM comments WHERE thread_id = ? ORDER BY posted_ts`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []api.Comment
+	for rows.Next() {
+		var c api.Comment
+		if err := rows.Scan(&c.ID, &c.ThreadID, &c.Content, &c.Creator, &c.PostedTS, &c.LastUpdatedTS); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanThread(s scanner) (*api.Thread, error) {
+	var t api.Thread
+	err := s.Scan(&t.ID, &t.WorkspaceID, &t.ChannelID, &t.Title, &t.Content, &t.Creator, &t.PostedTS,
+		&t.LastUpdatedTS, &t.CommentCount, &t.Starred, &t.Pinned, &t.Archived)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
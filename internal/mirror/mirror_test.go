@@ -0,0 +1,206 @@
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+func TestOpenReadOnly(t *testing.T) {
+	for _, name := range []string{"mirror.db", "what?.db", "a#b.db", "100%.db", "with space.db"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			m, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.db.Exec(`INSERT INTO workspaces (id, name) VALUES (1, 'Acme')`); err != nil {
+				t.Fatal(err)
+			}
+			m.Close()
+
+			ro, err := OpenReadOnly(path)
+			if err != nil {
+				t.Fatalf("OpenReadOnly(%q): %v", path, err)
+			}
+			defer ro.Close()
+			_, rows, err := ro.Query(`SELECT name FROM workspaces`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 1 || rows[0][0] != "Acme" {
+				t.Errorf("read %v from %q, want the row written to it", rows, name)
+			}
+			if _, _, err := ro.Query(`DELETE FROM workspaces`); err == nil {
+				t.Error("write through a read-only mirror succeeded")
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range entries {
+				if e.Name() != name && !strings.HasPrefix(e.Name(), name+"-") {
+					t.Errorf("opening %q created %q", name, e.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestOpenReadOnlyMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.db")
+	if _, err := OpenReadOnly(path); err == nil {
+		t.Fatal("OpenReadOnly of a missing file succeeded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("OpenReadOnly created the missing file")
+	}
+}
+
+// fakeTwist serves the endpoints Sync reads for workspace 1.
+type fakeTwist struct {
+	mu       sync.Mutex
+	channels []api.Channel
+	threads  []api.Thread
+	comments map[int][]api.Comment
+}
+
+func (f *fakeTwist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := r.URL.Query()
+	var v interface{} = []struct{}{}
+	switch strings.TrimPrefix(r.URL.Path, "/api/v3") {
+	case "/workspaces/get":
+		v = []api.Workspace{{ID: 1, Name: "Acme"}}
+	case "/channels/get":
+		if q.Get("archived") == "" {
+			v = f.channels
+		}
+	case "/threads/get":
+		id, _ := strconv.Atoi(q.Get("channel_id"))
+		var threads []api.Thread
+		for _, t := range f.threads {
+			if t.ChannelID == id {
+				threads = append(threads, t)
+			}
+		}
+		v = threads
+	case "/comments/get":
+		id, _ := strconv.Atoi(q.Get("thread_id"))
+		v = f.comments[id]
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+func newFakeClient(t *testing.T, h http.Handler) *api.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+
+	client := api.NewClient("test-token")
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return redirectTransport{target: target, next: next}
+	})
+	return client
+}
+
+func TestSyncPrunes(t *testing.T) {
+	fake := &fakeTwist{
+		channels: []api.Channel{
+			{ID: 10, WorkspaceID: 1, Name: "general"},
+			{ID: 20, WorkspaceID: 1, Name: "random"},
+		},
+		threads: []api.Thread{
+			{ID: 100, ChannelID: 10, WorkspaceID: 1, LastUpdatedTS: 1000},
+			{ID: 101, ChannelID: 10, WorkspaceID: 1, LastUpdatedTS: 1000},
+			{ID: 200, ChannelID: 20, WorkspaceID: 1, LastUpdatedTS: 1000},
+		},
+		comments: map[int][]api.Comment{
+			100: {{ID: 1, ThreadID: 100}},
+			101: {{ID: 2, ThreadID: 101}},
+			200: {{ID: 3, ThreadID: 200}, {ID: 4, ThreadID: 200}},
+		},
+	}
+	client := newFakeClient(t, fake)
+
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// A channel of another workspace is never pruned by a sync of this one.
+	if _, err := m.db.Exec(`INSERT INTO channels (id, workspace_id, name) VALUES (30, 2, 'elsewhere')`); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := m.Sync(client, 1, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if stats.ThreadsUpdated != 3 || stats.Comments != 4 || stats.ThreadsRemoved != 0 {
+		t.Errorf("first sync = %+v, want 3 threads and 4 comments", stats)
+	}
+
+	// Thread 101 is deleted and channel 20 is gone along with its thread.
+	fake.mu.Lock()
+	fake.channels = fake.channels[:1]
+	fake.threads = fake.threads[:1]
+	fake.mu.Unlock()
+
+	stats, err = m.Sync(client, 1, nil)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if stats.ThreadsRemoved != 2 || stats.ThreadsUnchanged != 1 {
+		t.Errorf("second sync = %+v, want 2 threads removed and 1 unchanged", stats)
+	}
+
+	count := func(query string) int {
+		t.Helper()
+		var n int
+		if err := m.db.QueryRow(query).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := count(`SELECT COUNT(*) FROM threads`); n != 1 {
+		t.Errorf("%d threads left, want 1", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM comments WHERE thread_id != 100`); n != 0 {
+		t.Errorf("%d comments of removed threads left", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM comments WHERE thread_id = 100`); n != 1 {
+		t.Errorf("thread 100 has %d comments, want its 1 comment kept", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM channels WHERE id = 20`); n != 0 {
+		t.Error("removed channel 20 is still stored")
+	}
+	if n := count(`SELECT COUNT(*) FROM channels WHERE id = 30`); n != 1 {
+		t.Error("channel of workspace 2 was pruned")
+	}
+}
@@ -0,0 +1,315 @@
+package mirror
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/intelligrit/twist-cli/pkg/api"
+)
+
+type SyncStats struct {
+	Channels               int
+	ThreadsUpdated         int
+	ThreadsUnchanged       int
+	ThreadsRemoved         int
+	Comments               int
+	ConversationsUpdated   int
+	ConversationsUnchanged int
+	Messages               int
+}
+
+// Sync mirrors a workspace into the database. Comments and messages are only
+// re-fetched for threads and conversations whose last-updated timestamp is
+// newer than the copy already stored. Channels and threads no longer listed
+// by the server are removed.
+func (m *Mirror) Sync(client *api.Client, workspaceID int, log io.Writer) (*SyncStats, error) {
+	if log == nil {
+		log = io.Discard
+	}
+	stats := &SyncStats{}
+
+	workspaces, err := client.GetWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+	for _, ws := range workspaces {
+		if ws.ID != workspaceID {
+			continue
+		}
+		if _, err := m.db.Exec(`INSERT OR REPLACE INTO workspaces (id, name, plan, created_ts) VALUES (?, ?, ?, ?)`,
+			ws.ID, ws.Name, ws.Plan, ws.CreatedTS); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := client.GetWorkspaceUsers(workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	for _, u := range users {
+		if _, err := m.db.Exec(`INSERT OR REPLACE INTO users (id, workspace_id, name, email, user_type, bot, removed)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, u.ID, workspaceID, u.Name, u.Email, u.UserType, u.Bot, u.Removed); err != nil {
+			return nil, err
+		}
+	}
+
+	channels, err := client.GetChannels(workspaceID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+	archived, err := client.GetChannels(workspaceID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived channels: %w", err)
+	}
+	channels = append(channels, archived...)
+
+	channelIDs := make([]int, len(channels))
+	for i, ch := range channels {
+		channelIDs[i] = ch.ID
+	}
+	removed, err := m.pruneChannels(workspaceID, channelIDs)
+	if err != nil {
+		return stats, err
+	}
+	stats.ThreadsRemoved += removed
+
+	for _, ch := range channels {
+		if _, err := m.db.Exec(`INSERT OR REPLACE INTO channels (id, workspace_id, name, description, public, archived, created_ts)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, ch.ID, ch.WorkspaceID, ch.Name, ch.Description, ch.Public, ch.Archived, ch.CreatedTS); err != nil {
+			return stats, err
+		}
+		stats.Channels++
+		fmt.Fprintf(log, "Channel #%d %s\n", ch.ID, ch.Name)
+
+		threads, err := client.GetThreads(ch.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get threads for channel %d: %w", ch.ID, err)
+		}
+		threadIDs := make([]int, len(threads))
+		for i, t := range threads {
+			threadIDs[i] = t.ID
+		}
+		removed, err := m.pruneThreads(ch.ID, threadIDs)
+		if err != nil {
+			return stats, err
+		}
+		stats.ThreadsRemoved += removed
+
+		for _, t := range threads {
+			stored, found, err := m.storedTS(`SELECT last_updated_ts FROM threads WHERE id = ?`, t.ID)
+			if err != nil {
+				return stats, err
+			}
+			if found && stored >= t.LastUpdatedTS {
+				stats.ThreadsUnchanged++
+				if err := m.saveThread(t, nil); err != nil {
+					return stats, err
+				}
+				continue
+			}
+
+			comments, err := client.GetComments(t.ID)
+			if err != nil {
+				return stats, fmt.Errorf("failed to get comments for thread %d: %w", t.ID, err)
+			}
+			if err := m.saveThread(t, comments); err != nil {
+				return stats, err
+			}
+			stats.ThreadsUpdated++
+			stats.Comments += len(comments)
+		}
+	}
+
+	conversations, err := client.GetConversations()
+	if err != nil {
+		return stats, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	for _, conv := range conversations {
+		if conv.WorkspaceID != workspaceID {
+			continue
+		}
+		stored, found, err := m.storedTS(`SELECT last_active_ts FROM conversations WHERE id = ?`, conv.ID)
+		if err != nil {
+			return stats, err
+		}
+		if found && stored >= conv.LastActiveTS {
+			stats.ConversationsUnchanged++
+			continue
+		}
+
+		messages, err := client.GetConversationMessages(conv.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get messages for conversation %d: %w", conv.ID, err)
+		}
+		if err := m.saveConversation(conv, messages); err != nil {
+			return stats, err
+		}
+		stats.ConversationsUpdated++
+		stats.Messages += len(messages)
+	}
+
+	if _, err := m.db.Exec(`INSERT OR REPLACE INTO sync_state (workspace_id, last_sync_ts) VALUES (?, ?)`,
+		workspaceID, time.Now().Unix()); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// pruneChannels deletes the stored channels of a workspace that are not in
+// keep, along with their threads and comments, and returns the number of
+// threads deleted.
+func (m *Mirror) pruneChannels(workspaceID int, keep []int) (int, error) {
+	stale, err := m.staleIDs(`SELECT id FROM channels WHERE workspace_id = ?`, workspaceID, keep)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, id := range stale {
+		threads, err := m.staleIDs(`SELECT id FROM threads WHERE channel_id = ?`, id, nil)
+		if err != nil {
+			return removed, err
+		}
+		n, err := m.deleteThreads(threads)
+		removed += n
+		if err != nil {
+			return removed, err
+		}
+		if _, err := m.db.Exec(`DELETE FROM channels WHERE id = ?`, id); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// pruneThreads deletes the stored threads of a channel that are not in keep,
+// along with their comments, and returns how many were deleted.
+func (m *Mirror) pruneThreads(channelID int, keep []int) (int, error) {
+	stale, err := m.staleIDs(`SELECT id FROM threads WHERE channel_id = ?`, channelID, keep)
+	if err != nil {
+		return 0, err
+	}
+	return m.deleteThreads(stale)
+}
+
+// staleIDs returns the IDs selected by query that are not in keep.
+func (m *Mirror) staleIDs(query string, arg int, keep []int) ([]int, error) {
+	kept := make(map[int]bool, len(keep))
+	for _, id := range keep {
+		kept[id] = true
+	}
+
+	rows, err := m.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stale []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		if !kept[id] {
+			stale = append(stale, id)
+		}
+	}
+	return stale, rows.Err()
+}
+
+func (m *Mirror) deleteThreads(ids []int) (int, error) {
+	for i, id := range ids {
+		tx, err := m.db.Begin()
+		if err != nil {
+			return i, err
+		}
+		if _, err := tx.Exec(`DELETE FROM comments WHERE thread_id = ?`, id); err != nil {
+			tx.Rollback()
+			return i, err
+		}
+		if _, err := tx.Exec(`DELETE FROM threads WHERE id = ?`, id); err != nil {
+			tx.Rollback()
+			return i, err
+		}
+		if err := tx.Commit(); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+func (m *Mirror) storedTS(query string, id int) (int64, bool, error) {
+	var ts int64
+	err := m.db.QueryRow(query, id).Scan(&ts)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return ts, true, nil
+}
+
+// saveThread upserts a thread. When comments is non-nil the stored comments
+// of the thread are replaced with it.
+func (m *Mirror) saveThread(t api.Thread, comments []api.Comment) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO threads (id, workspace_id, channel_id, title, content, creator,
+		posted_ts, last_updated_ts, comment_count, starred, pinned, archived)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, t.WorkspaceID, t.ChannelID, t.Title, t.Content, t.Creator,
+		t.PostedTS, t.LastUpdatedTS, t.CommentCount, t.Starred, t.Pinned, t.Archived); err != nil {
+		return err
+	}
+
+	if comments != nil {
+		if _, err := tx.Exec(`DELETE FROM comments WHERE thread_id = ?`, t.ID); err != nil {
+			return err
+		}
+		for _, c := range comments {
+			if _, err := tx.Exec(`INSERT OR REPLACE INTO comments (id, thread_id, channel_id, workspace_id, content,
+				creator, posted_ts, last_updated_ts) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				c.ID, t.ID, t.ChannelID, t.WorkspaceID, c.Content, c.Creator, c.PostedTS, c.LastUpdatedTS); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Mirror) saveConversation(conv api.Conversation, messages []api.ConversationMessage) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO conversations (id, workspace_id, user_ids, message_count,
+		created_ts, last_active_ts, archived, muted) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		conv.ID, conv.WorkspaceID, joinIDs(conv.UserIDs), conv.MessageCount,
+		conv.CreatedTS, conv.LastActiveTS, conv.IsArchived, conv.IsMuted); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM messages WHERE conversation_id = ?`, conv.ID); err != nil {
+		return err
+	}
+	for _, msg := range messages {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO messages (id, conversation_id, content, user_id, created_ts)
+			VALUES (?, ?, ?, ?, ?)`, msg.ID, conv.ID, msg.Content, msg.UserID, msg.CreatedTS); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,109 @@
+// Package progress draws byte-count progress bars on a terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	barWidth    = 30
+	redrawEvery = 100 * time.Millisecond
+)
+
+// Bar is a single-line progress bar. It is safe for concurrent use, so one
+// bar can track several transfers at once. Nothing is drawn unless the
+// writer is a terminal.
+type Bar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	label    string
+	total    int64
+	done     int64
+	enabled  bool
+	lastDraw time.Time
+}
+
+// New returns a bar counting up to total bytes; a total of zero or less
+// shows the byte count without a percentage.
+func New(w io.Writer, label string, total int64) *Bar {
+	return &Bar{w: w, label: label, total: total, enabled: IsTerminal(w)}
+}
+
+// IsTerminal reports whether w is a character device such as a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Add records n more bytes.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	if time.Since(b.lastDraw) >= redrawEvery {
+		b.draw()
+	}
+}
+
+// Write counts len(p) bytes, so a bar can be used with io.TeeReader or
+// io.MultiWriter.
+func (b *Bar) Write(p []byte) (int, error) {
+	b.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// Finish draws the final state and ends the line.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.enabled {
+		return
+	}
+	b.draw()
+	fmt.Fprintln(b.w)
+}
+
+func (b *Bar) draw() {
+	b.lastDraw = time.Now()
+	if !b.enabled {
+		return
+	}
+	if b.total <= 0 {
+		fmt.Fprintf(b.w, "\r\033[K%s %s", b.label, FormatBytes(b.done))
+		return
+	}
+
+	frac := float64(b.done) / float64(b.total)
+	if frac > 1 {
+		frac = 1
+	}
+	filled := int(frac * barWidth)
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+	fmt.Fprintf(b.w, "\r\033[K%s [%s] %3.0f%% %s/%s", b.label, bar, frac*100,
+		FormatBytes(b.done), FormatBytes(b.total))
+}
+
+// FormatBytes renders a byte count as B, KB, MB or GB.
+func FormatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.2f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,83 @@
+// Package retention loads retention policies.
+//
+// A policy is a YAML file such as:
+//
+//	workspace: 12345
+//	rules:
+//	  - name: stale alerts
+//	    channel: alerts
+//	    action: archive
+//	    where: last_updated < 30d
+//	  - name: old bot threads
+//	    channel: "*"
+//	    action: delete
+//	    where: bot && posted < 1y
+//
+// channel is a channel name or ID, or "*" for every channel of the
+// workspace. where uses the expression language of the filter package. Rules
+// are evaluated in order and the first rule matching a thread decides what
+// happens to it.
+package retention
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/intelligrit/twist-cli/internal/filter"
+	"gopkg.in/yaml.v3"
+)
+
+type Policy struct {
+	Workspace int    `yaml:"workspace"`
+	Rules     []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Name    string `yaml:"name"`
+	Channel string `yaml:"channel"`
+	Action  string `yaml:"action"`
+	Where   string `yaml:"where"`
+
+	Filter *filter.Filter `yaml:"-"`
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	if p.Workspace == 0 {
+		return nil, fmt.Errorf("policy has no workspace")
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("policy has no rules")
+	}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if r.Channel == "" {
+			return nil, fmt.Errorf("%s: channel is required (use \"*\" for all channels)", r.Name)
+		}
+		if r.Action != "archive" && r.Action != "delete" {
+			return nil, fmt.Errorf("%s: action must be archive or delete", r.Name)
+		}
+		if r.Where == "" {
+			return nil, fmt.Errorf("%s: where is required", r.Name)
+		}
+		if r.Filter, err = filter.Parse(r.Where); err != nil {
+			return nil, fmt.Errorf("%s: invalid where: %w", r.Name, err)
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,129 @@
+// Package savedsearch stores named server-side searches and, for each one,
+// the results already reported so that repeated runs can show only new hits.
+package savedsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/intelligrit/twist-cli/internal/config"
+)
+
+const (
+	searchesFile = "searches.json"
+	stateFile    = "searches-state.json"
+)
+
+// Search holds the arguments and filter flags of a search command.
+type Search struct {
+	Name           string `json:"name"`
+	Kind           string `json:"kind"`
+	WorkspaceID    int    `json:"workspace_id"`
+	Query          string `json:"query"`
+	ChannelID      int    `json:"channel_id,omitempty"`
+	Author         string `json:"author,omitempty"`
+	Since          string `json:"since,omitempty"`
+	Until          string `json:"until,omitempty"`
+	HasAttachments bool   `json:"has_attachments,omitempty"`
+	In             string `json:"in,omitempty"`
+	Limit          int    `json:"limit,omitempty"`
+}
+
+// State records which hits of a saved search have been reported, keyed as
+// "<kind>:<id>".
+type State struct {
+	LastRunTS int64           `json:"last_run_ts"`
+	Seen      map[string]bool `json:"seen"`
+}
+
+type Store struct {
+	Searches map[string]Search
+	States   map[string]*State
+}
+
+// Load reads the saved searches and their state from the config directory.
+// Missing files yield an empty store.
+func Load() (*Store, error) {
+	s := &Store{
+		Searches: make(map[string]Search),
+		States:   make(map[string]*State),
+	}
+	if err := readFile(searchesFile, &s.Searches); err != nil {
+		return nil, fmt.Errorf("failed to read saved searches: %w", err)
+	}
+	if err := readFile(stateFile, &s.States); err != nil {
+		return nil, fmt.Errorf("failed to read saved search state: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	if err := writeFile(searchesFile, s.Searches); err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+	if err := writeFile(stateFile, s.States); err != nil {
+		return fmt.Errorf("failed to write saved search state: %w", err)
+	}
+	return nil
+}
+
+// Names returns the saved search names in alphabetical order.
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.Searches))
+	for name := range s.Searches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Store) Delete(name string) {
+	delete(s.Searches, name)
+	delete(s.States, name)
+}
+
+// State returns the state of the named search, creating it if needed.
+func (s *Store) State(name string) *State {
+	st, ok := s.States[name]
+	if !ok || st == nil {
+		st = &State{Seen: make(map[string]bool)}
+		s.States[name] = st
+	}
+	if st.Seen == nil {
+		st.Seen = make(map[string]bool)
+	}
+	return st
+}
+
+func readFile(name string, v interface{}) error {
+	path, err := config.Path(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func writeFile(name string, v interface{}) error {
+	path, err := config.Path(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
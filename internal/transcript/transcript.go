@@ -0,0 +1,156 @@
+// Package transcript renders conversations as plain text, Markdown, JSON or
+// HTML transcripts.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+var Formats = []string{"txt", "md", "json", "html"}
+
+type Transcript struct {
+	ConversationID int        `json:"conversation_id"`
+	WorkspaceID    int        `json:"workspace_id"`
+	Title          string     `json:"title,omitempty"`
+	Participants   []string   `json:"participants"`
+	Since          *time.Time `json:"since,omitempty"`
+	Until          *time.Time `json:"until,omitempty"`
+	ExportedAt     time.Time  `json:"exported_at"`
+	Messages       []Message  `json:"messages"`
+}
+
+type Message struct {
+	ID          int          `json:"id"`
+	AuthorID    int          `json:"author_id"`
+	Author      string       `json:"author"`
+	Time        time.Time    `json:"time"`
+	Content     string       `json:"content"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+type Attachment struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+}
+
+// Write renders t in the given format.
+func Write(w io.Writer, format string, t *Transcript) error {
+	switch format {
+	case "txt":
+		return writeText(w, t)
+	case "md":
+		return writeMarkdown(w, t)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	case "html":
+		return htmlTemplate.Execute(w, t)
+	}
+	return fmt.Errorf("unknown format %q: must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Heading is the conversation title, or its ID when it has none.
+func (t *Transcript) Heading() string {
+	if t.Title != "" {
+		return t.Title
+	}
+	return fmt.Sprintf("Conversation %d", t.ConversationID)
+}
+
+// Period describes the date range the transcript covers.
+func (t *Transcript) Period() string {
+	switch {
+	case t.Since != nil && t.Until != nil:
+		return fmt.Sprintf("%s to %s", t.Since.Format("2006-01-02"), t.Until.Format("2006-01-02"))
+	case t.Since != nil:
+		return "since " + t.Since.Format("2006-01-02")
+	case t.Until != nil:
+		return "until " + t.Until.Format("2006-01-02")
+	}
+	return "all messages"
+}
+
+func writeText(w io.Writer, t *Transcript) error {
+	fmt.Fprintf(w, "%s\n", t.Heading())
+	fmt.Fprintf(w, "Participants: %s\n", strings.Join(t.Participants, ", "))
+	fmt.Fprintf(w, "Period: %s\n", t.Period())
+	fmt.Fprintf(w, "Exported: %s\n", t.ExportedAt.Format(timeLayout))
+	fmt.Fprintf(w, "Messages: %d\n\n", len(t.Messages))
+
+	for _, m := range t.Messages {
+		fmt.Fprintf(w, "[%s] %s:\n", m.Time.Format(timeLayout), m.Author)
+		for _, line := range strings.Split(m.Content, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+		for _, a := range m.Attachments {
+			fmt.Fprintf(w, "    [attachment] %s (%s, %d bytes) %s\n", a.Title, a.MimeType, a.Size, a.URL)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func writeMarkdown(w io.Writer, t *Transcript) error {
+	fmt.Fprintf(w, "# %s\n\n", t.Heading())
+	fmt.Fprintf(w, "- **Participants:** %s\n", strings.Join(t.Participants, ", "))
+	fmt.Fprintf(w, "- **Period:** %s\n", t.Period())
+	fmt.Fprintf(w, "- **Exported:** %s\n", t.ExportedAt.Format(timeLayout))
+	fmt.Fprintf(w, "- **Messages:** %d\n\n", len(t.Messages))
+
+	for _, m := range t.Messages {
+		fmt.Fprintf(w, "### %s — %s\n\n", m.Author, m.Time.Format(timeLayout))
+		fmt.Fprintf(w, "%s\n\n", m.Content)
+		for _, a := range m.Attachments {
+			fmt.Fprintf(w, "- 📎 [%s](%s) (%s, %d bytes)\n", a.Title, a.URL, a.MimeType, a.Size)
+		}
+		if len(m.Attachments) > 0 {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"fmtTime": func(t time.Time) string { return t.Format(timeLayout) },
+	"join":    strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Heading}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; }
+.meta { color: #555; }
+.message { border-top: 1px solid #ddd; padding: 0.5em 0; }
+.author { font-weight: bold; }
+.time { color: #777; font-size: 0.9em; }
+.content { white-space: pre-wrap; margin: 0.3em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Heading}}</h1>
+<p class="meta">
+Participants: {{join .Participants ", "}}<br>
+Period: {{.Period}}<br>
+Exported: {{fmtTime .ExportedAt}}<br>
+Messages: {{len .Messages}}
+</p>
+{{range .Messages}}<div class="message">
+<span class="author">{{.Author}}</span> <span class="time">{{fmtTime .Time}}</span>
+<div class="content">{{.Content}}</div>
+{{range .Attachments}}<div class="attachment">Attachment: <a href="{{.URL}}">{{.Title}}</a> ({{.MimeType}}, {{.Size}} bytes)</div>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
@@ -1,14 +1,18 @@
 package api
 
 import (
-	"bytes"
+	"bufio"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Attachment struct {
@@ -27,35 +31,51 @@ func (c *Client) UploadAttachment(targetType string, targetID int, filePath stri
 	}
 	defer file.Close()
 
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("failed to copy file: %w", err)
-	}
+	return c.UploadAttachmentFrom(targetType, targetID, filepath.Base(filePath), file, nil)
+}
 
+// UploadAttachmentFrom uploads the contents of r as a file called name. The
+// multipart body is streamed from r rather than built in memory, so r may be
+// arbitrarily large or a stream such as stdin. progress, if not nil, is
+// called with the number of bytes read from r as they are sent.
+//
+// An empty targetType uploads the file without attaching it to anything, so
+// that it can be passed to CreateThread, PostComment or
+// SendConversationMessage and arrive together with the post.
+func (c *Client) UploadAttachmentFrom(targetType string, targetID int, name string, r io.Reader, progress func(n int64)) (*Attachment, error) {
+	var targetField string
 	if targetType == "thread" {
-		writer.WriteField("thread_id", fmt.Sprintf("%d", targetID))
+		targetField = "thread_id"
 	} else if targetType == "comment" {
-		writer.WriteField("comment_id", fmt.Sprintf("%d", targetID))
+		targetField = "comment_id"
 	} else if targetType == "conversation" {
-		writer.WriteField("conversation_id", fmt.Sprintf("%d", targetID))
-	} else {
+		targetField = "conversation_id"
+	} else if targetType != "" {
 		return nil, fmt.Errorf("invalid target type: must be 'thread', 'comment', or 'conversation'")
 	}
 
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	br := bufio.NewReader(r)
+	contentType := DetectContentType(name, br)
+	if progress != nil {
+		r = io.TeeReader(br, progressWriter(progress))
+	} else {
+		r = br
 	}
 
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		err := writeUploadForm(writer, targetField, targetID, name, contentType, r)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
 	url := BaseURL + "/attachments/upload"
-	req, err := http.NewRequest("POST", url, &buf)
+	req, err := http.NewRequest("POST", url, pr)
 	if err != nil {
+		pr.Close()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
@@ -81,41 +101,230 @@ func (c *Client) UploadAttachment(targetType string, targetID int, filePath stri
 	return &attachment, nil
 }
 
-func (c *Client) DownloadAttachment(id int, outputPath string) error {
+func writeUploadForm(writer *multipart.Writer, targetField string, targetID int, name, contentType string, r io.Reader) error {
+	field, value := targetField, fmt.Sprintf("%d", targetID)
+	if targetField == "" {
+		id, err := newAttachmentID()
+		if err != nil {
+			return err
+		}
+		field, value = "attachment_id", id
+	}
+	if err := writer.WriteField(field, value); err != nil {
+		return fmt.Errorf("failed to write form field: %w", err)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(name)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
+
+// newAttachmentID returns a random UUID identifying an upload that is not
+// attached to anything yet.
+func newAttachmentID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate attachment ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// DetectContentType guesses a file's MIME type from its extension, falling
+// back to sniffing the first bytes buffered in br.
+func DetectContentType(name string, br *bufio.Reader) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	head, _ := br.Peek(512)
+	return http.DetectContentType(head)
+}
+
+func (c *Client) GetAttachment(id int) (*Attachment, error) {
 	endpoint := fmt.Sprintf("/attachments/getone?id=%d", id)
 	body, err := c.doRequest("GET", endpoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var attachment Attachment
 	if err := json.Unmarshal(body, &attachment); err != nil {
-		return fmt.Errorf("failed to parse attachment response: %w", err)
+		return nil, fmt.Errorf("failed to parse attachment response: %w", err)
 	}
 
-	resp, err := http.Get(attachment.URL)
+	return &attachment, nil
+}
+
+func (c *Client) DownloadAttachment(id int, outputPath string) error {
+	attachment, err := c.GetAttachment(id)
 	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	return c.DownloadAttachmentFile(attachment, outputPath, nil)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+// DownloadAttachmentFile downloads an attachment to outputPath through a
+// ".part" file that is renamed into place once complete. A ".part" file left
+// by an interrupted download is resumed with a Range request guarded by
+// If-Range, using the ETag or Last-Modified value of the first response
+// (kept in a ".part.validator" file), so that a file changed in the meantime
+// is downloaded again from the start. The result must match attachment.Size
+// when the API reports one. progress, if not nil, is called with the number
+// of bytes received, including any resumed ones.
+func (c *Client) DownloadAttachmentFile(attachment *Attachment, outputPath string, progress func(n int64)) error {
+	partPath := outputPath + ".part"
+	validatorPath := partPath + ".validator"
+
+	var offset int64
+	if fi, err := os.Stat(partPath); err == nil {
+		offset = fi.Size()
 	}
+	validator, err := os.ReadFile(validatorPath)
+	if err != nil || len(validator) == 0 || (attachment.Size > 0 && offset > attachment.Size) {
+		// Without a validator the part file cannot be safely resumed.
+		os.Remove(partPath)
+		offset = 0
+	}
+
+	if attachment.Size <= 0 || offset < attachment.Size {
+		req, err := http.NewRequest("GET", attachment.URL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", string(validator))
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to download file: %w", err)
+		}
+		defer resp.Body.Close()
+
+		flags := os.O_CREATE | os.O_WRONLY
+		switch resp.StatusCode {
+		case http.StatusPartialContent:
+			flags |= os.O_APPEND
+		case http.StatusOK:
+			// A fresh download, or the server ignored the range or the file
+			// changed; start over.
+			flags |= os.O_TRUNC
+			offset = 0
+			if err := saveValidator(validatorPath, resp.Header); err != nil {
+				return err
+			}
+		case http.StatusRequestedRangeNotSatisfiable:
+			// The part file already holds the whole attachment.
+			flags |= os.O_APPEND
+			resp.Body = http.NoBody
+		default:
+			return fmt.Errorf("download failed with status %d", resp.StatusCode)
+		}
 
-	out, err := os.Create(outputPath)
+		out, err := os.OpenFile(partPath, flags, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		if progress != nil && offset > 0 {
+			progress(offset)
+		}
+		var dst io.Writer = out
+		if progress != nil {
+			dst = io.MultiWriter(out, progressWriter(progress))
+		}
+		_, copyErr := io.Copy(dst, resp.Body)
+		closeErr := out.Close()
+		if copyErr != nil {
+			return fmt.Errorf("failed to write file: %w", copyErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to write file: %w", closeErr)
+		}
+	} else if progress != nil {
+		progress(offset)
+	}
+
+	fi, err := os.Stat(partPath)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return fmt.Errorf("failed to check downloaded file: %w", err)
+	}
+	if attachment.Size > 0 && fi.Size() != attachment.Size {
+		os.Remove(partPath)
+		os.Remove(validatorPath)
+		return fmt.Errorf("downloaded %d bytes but attachment is %d bytes", fi.Size(), attachment.Size)
 	}
-	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	if err := os.Rename(partPath, outputPath); err != nil {
+		return fmt.Errorf("failed to move file into place: %w", err)
 	}
+	os.Remove(validatorPath)
+	return nil
+}
 
+// saveValidator records the strong ETag, or else the Last-Modified value, of
+// a response for a later If-Range. Without either, any old validator is
+// removed so that the download is not resumed.
+func saveValidator(path string, header http.Header) error {
+	validator := header.Get("ETag")
+	if strings.HasPrefix(validator, "W/") {
+		validator = ""
+	}
+	if validator == "" {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove download validator: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(validator), 0644); err != nil {
+		return fmt.Errorf("failed to save download validator: %w", err)
+	}
 	return nil
 }
 
+// OpenAttachment starts downloading an attachment's contents. The caller
+// must close the returned body.
+func (c *Client) OpenAttachment(attachment *Attachment) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", attachment.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+type progressWriter func(n int64)
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	p(int64(len(b)))
+	return len(b), nil
+}
+
 func (c *Client) GetAttachments(targetType string, targetID int) ([]Attachment, error) {
 	var endpoint string
 	if targetType == "thread" {
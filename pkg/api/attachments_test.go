@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fileServer serves content at /file with the given ETag, honouring Range and
+// If-Range, and records the Range header of each request.
+type fileServer struct {
+	content []byte
+	etag    string
+	ranges  []string
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(s.content))
+}
+
+func TestDownloadAttachmentFile(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+
+	tests := []struct {
+		name      string
+		etag      string
+		part      string
+		validator string
+		size      int64
+		wantRange string
+		wantErr   string
+	}{
+		{
+			name:      "fresh download",
+			etag:      `"v1"`,
+			size:      1000,
+			wantRange: "",
+		},
+		{
+			name:      "resume with a matching validator",
+			etag:      `"v1"`,
+			part:      string(content[:400]),
+			validator: `"v1"`,
+			size:      1000,
+			wantRange: "bytes=400-",
+		},
+		{
+			// The server answers 200 with the whole file and the part file
+			// is replaced rather than appended to.
+			name:      "file changed since the part was saved",
+			etag:      `"v2"`,
+			part:      "stale data",
+			validator: `"v1"`,
+			size:      1000,
+			wantRange: "bytes=10-",
+		},
+		{
+			name:      "part file without a validator",
+			etag:      `"v1"`,
+			part:      "unverified",
+			size:      1000,
+			wantRange: "",
+		},
+		{
+			// Without a size from the API the request is still made; the
+			// server answers 416 and the part file is kept as it is.
+			name:      "part file already complete",
+			etag:      `"v1"`,
+			part:      string(content),
+			validator: `"v1"`,
+			wantRange: "bytes=1000-",
+		},
+		{
+			name:    "size mismatch",
+			etag:    `"v1"`,
+			size:    999,
+			wantErr: "downloaded 1000 bytes but attachment is 999 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := &fileServer{content: content, etag: tt.etag}
+			srv := httptest.NewServer(fs)
+			defer srv.Close()
+
+			out := filepath.Join(t.TempDir(), "out.bin")
+			if tt.part != "" {
+				if err := os.WriteFile(out+".part", []byte(tt.part), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.validator != "" {
+				if err := os.WriteFile(out+".part.validator", []byte(tt.validator), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var progress int64
+			client := NewClient("test-token")
+			err := client.DownloadAttachmentFile(&Attachment{URL: srv.URL + "/file", Size: tt.size}, out, func(n int64) {
+				progress += n
+			})
+
+			if len(fs.ranges) != 1 || fs.ranges[0] != tt.wantRange {
+				t.Errorf("requested ranges %q, want [%q]", fs.ranges, tt.wantRange)
+			}
+			for _, leftover := range []string{out + ".part", out + ".part.validator"} {
+				if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+					t.Errorf("%s left behind", filepath.Base(leftover))
+				}
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Stat(out); !os.IsNotExist(err) {
+					t.Error("output file written despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded %d bytes %.20q..., want the %d byte file", len(got), got, len(content))
+			}
+			if progress != int64(len(content)) {
+				t.Errorf("progress reported %d bytes, want %d", progress, len(content))
+			}
+		})
+	}
+}
+
+func TestDownloadAttachmentFileSkipsComplete(t *testing.T) {
+	fs := &fileServer{content: []byte("done"), etag: `"v1"`}
+	srv := httptest.NewServer(fs)
+	defer srv.Close()
+
+	out := filepath.Join(t.TempDir(), "out.bin")
+	os.WriteFile(out+".part", []byte("done"), 0644)
+	os.WriteFile(out+".part.validator", []byte(`"v1"`), 0644)
+
+	client := NewClient("test-token")
+	if err := client.DownloadAttachmentFile(&Attachment{URL: srv.URL + "/file", Size: 4}, out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.ranges) != 0 {
+		t.Errorf("made %d requests for a part file that was already complete", len(fs.ranges))
+	}
+	if got, _ := os.ReadFile(out); string(got) != "done" {
+		t.Errorf("output = %q, want %q", got, "done")
+	}
+}
@@ -14,6 +14,7 @@ type Channel struct {
 	WorkspaceID int    `json:"workspace_id"`
 	Public      bool   `json:"public"`
 	Archived    bool   `json:"archived"`
+	UserIDs     []int  `json:"user_ids"`
 	Color       int    `json:"color"`
 	Icon        int    `json:"icon"`
 	CreatedTS   int64  `json:"created_ts"`
@@ -32,6 +32,16 @@ func NewClient(token string) *Client {
 	}
 }
 
+// WrapTransport installs a RoundTripper around the one used for every
+// request, e.g. for logging.
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = wrap(next)
+}
+
 func (c *Client) doRequest(method, endpoint string) ([]byte, error) {
 	url := BaseURL + endpoint
 	req, err := http.NewRequest(method, url, nil)
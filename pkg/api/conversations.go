@@ -5,23 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Conversation struct {
-	ID           int   `json:"id"`
-	UserIDs      []int `json:"user_ids"`
-	MessageCount int   `json:"message_count"`
-	CreatedTS    int64 `json:"created_ts"`
-	IsArchived   bool  `json:"is_archived"`
-	IsMuted      bool  `json:"is_muted"`
+	ID           int    `json:"id"`
+	WorkspaceID  int    `json:"workspace_id"`
+	UserIDs      []int  `json:"user_ids"`
+	Title        string `json:"title"`
+	MessageCount int    `json:"message_count"`
+	LastObjIndex int    `json:"last_obj_index"`
+	Snippet      string `json:"snippet"`
+	SnippetBy    int    `json:"snippet_creator"`
+	CreatedTS    int64  `json:"created_ts"`
+	LastActiveTS int64  `json:"last_active_ts"`
+	IsArchived   bool   `json:"is_archived"`
+	IsMuted      bool   `json:"is_muted"`
 }
 
 type ConversationMessage struct {
-	ID             int    `json:"id"`
-	ConversationID int    `json:"conversation_id"`
-	Content        string `json:"content"`
-	UserID         int    `json:"user_id"`
-	CreatedTS      int64  `json:"created_ts"`
+	ID             int          `json:"id"`
+	ConversationID int          `json:"conversation_id"`
+	WorkspaceID    int          `json:"workspace_id"`
+	Content        string       `json:"content"`
+	UserID         int          `json:"user_id"`
+	CreatedTS      int64        `json:"created_ts"`
+	Attachments    []Attachment `json:"attachments"`
 }
 
 func (c *Client) GetConversations() ([]Conversation, error) {
@@ -38,6 +47,42 @@ func (c *Client) GetConversations() ([]Conversation, error) {
 	return conversations, nil
 }
 
+func (c *Client) GetConversation(id int) (*Conversation, error) {
+	endpoint := fmt.Sprintf("/conversations/getone?id=%d", id)
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversation Conversation
+	if err := json.Unmarshal(body, &conversation); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation response: %w", err)
+	}
+
+	return &conversation, nil
+}
+
+// UnreadConversation is the read position of a conversation with unread
+// messages: ObjIndex is the index of the last message read.
+type UnreadConversation struct {
+	ConversationID int `json:"conversation_id"`
+	ObjIndex       int `json:"obj_index"`
+}
+
+func (c *Client) GetUnreadConversations() ([]UnreadConversation, error) {
+	body, err := c.doRequest("GET", "/conversations/get_unread")
+	if err != nil {
+		return nil, err
+	}
+
+	var unread []UnreadConversation
+	if err := json.Unmarshal(body, &unread); err != nil {
+		return nil, fmt.Errorf("failed to parse unread conversations response: %w", err)
+	}
+
+	return unread, nil
+}
+
 func (c *Client) GetConversationMessages(conversationID int) ([]ConversationMessage, error) {
 	endpoint := fmt.Sprintf("/conversation_messages/get?conversation_id=%d", conversationID)
 	body, err := c.doRequest("GET", endpoint)
@@ -90,7 +135,7 @@ func (c *Client) GetOrCreateConversation(userIDs []int) (*Conversation, error) {
 	return &conversation, nil
 }
 
-func (c *Client) SendConversationMessage(conversationID int, content string, recipients []int) (*ConversationMessage, error) {
+func (c *Client) SendConversationMessage(conversationID int, content string, recipients []int, attachments ...Attachment) (*ConversationMessage, error) {
 	payload := map[string]interface{}{
 		"conversation_id": conversationID,
 		"content":         content,
@@ -100,6 +145,10 @@ func (c *Client) SendConversationMessage(conversationID int, content string, rec
 		payload["recipients"] = recipients
 	}
 
+	if len(attachments) > 0 {
+		payload["attachments"] = attachments
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal message data: %w", err)
@@ -132,6 +181,261 @@ func (c *Client) SendConversationMessage(conversationID int, content string, rec
 	return &message, nil
 }
 
+// GetConversationMessagesPage fetches one page of messages. Supported
+// options: newer_than_ts and older_than_ts (int64), limit (int) and
+// order_by ("asc" or "desc").
+func (c *Client) GetConversationMessagesPage(conversationID int, opts map[string]interface{}) ([]ConversationMessage, error) {
+	endpoint := fmt.Sprintf("/conversation_messages/get?conversation_id=%d", conversationID)
+	if ts, ok := opts["newer_than_ts"].(int64); ok {
+		endpoint += fmt.Sprintf("&newer_than_ts=%d", ts)
+	}
+	if ts, ok := opts["older_than_ts"].(int64); ok {
+		endpoint += fmt.Sprintf("&older_than_ts=%d", ts)
+	}
+	if limit, ok := opts["limit"].(int); ok {
+		endpoint += fmt.Sprintf("&limit=%d", limit)
+	}
+	if order, ok := opts["order_by"].(string); ok {
+		endpoint += "&order_by=" + url.QueryEscape(order)
+	}
+
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []ConversationMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse messages response: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (c *Client) GetConversationMessage(id int) (*ConversationMessage, error) {
+	endpoint := fmt.Sprintf("/conversation_messages/getone?id=%d", id)
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var message ConversationMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, fmt.Errorf("failed to parse message response: %w", err)
+	}
+
+	return &message, nil
+}
+
+func (c *Client) UpdateConversationMessage(id int, content string) (*ConversationMessage, error) {
+	payload := map[string]interface{}{
+		"id":      id,
+		"content": content,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/conversation_messages/update"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var message ConversationMessage
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, fmt.Errorf("failed to parse message response: %w", err)
+	}
+
+	return &message, nil
+}
+
+func (c *Client) DeleteConversationMessage(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/conversation_messages/remove"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateConversation(id int, updates map[string]interface{}) (*Conversation, error) {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	for k, v := range updates {
+		payload[k] = v
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update data: %w", err)
+	}
+
+	url := BaseURL + "/conversations/update"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var conversation Conversation
+	if err := json.NewDecoder(resp.Body).Decode(&conversation); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation response: %w", err)
+	}
+
+	return &conversation, nil
+}
+
+func (c *Client) AddConversationUsers(id int, userIDs []int) error {
+	payload := map[string]interface{}{
+		"id":       id,
+		"user_ids": userIDs,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/conversations/add_users"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) RemoveConversationUsers(id int, userIDs []int) error {
+	payload := map[string]interface{}{
+		"id":       id,
+		"user_ids": userIDs,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/conversations/remove_users"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) LeaveConversation(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/conversations/leave"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (c *Client) ArchiveConversation(id int) error {
 	payload := map[string]interface{}{
 		"id": id,
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// dryRunTransport lets read-only requests through and prints every other
+// request instead of sending it.
+type dryRunTransport struct {
+	next http.RoundTripper
+	out  io.Writer
+}
+
+// SetDryRun puts the client in dry-run mode: GET requests are sent as usual,
+// while other requests are written to out and answered with an empty JSON
+// object, so commands that make several changes show all of them. Anything
+// read back from such a response, such as the ID of a created thread, is zero.
+func (c *Client) SetDryRun(out io.Writer) {
+	c.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return &dryRunTransport{next: next, out: out}
+	})
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	fmt.Fprintf(t.out, "[dry-run] %s %s\n", req.Method, req.URL)
+	if req.Body != nil {
+		defer req.Body.Close()
+		if err := t.printBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func (t *dryRunTransport) printBody(req *http.Request) error {
+	contentType := req.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/json") {
+		// Drain the body so streamed uploads finish as they would when sent.
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		fmt.Fprintf(t.out, "[dry-run] <%s body not shown>\n", contentType)
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, body, "", "  "); err != nil {
+		pretty.Reset()
+		pretty.Write(body)
+	}
+	fmt.Fprintf(t.out, "%s\n", pretty.String())
+	return nil
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+func TestDryRun(t *testing.T) {
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		json.NewEncoder(w).Encode([]Workspace{{ID: 1, Name: "Acme"}})
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	var out bytes.Buffer
+	client := NewClient("test-token")
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return redirectTransport{target: target, next: next}
+	})
+	client.SetDryRun(&out)
+
+	workspaces, err := client.GetWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 1 || workspaces[0].Name != "Acme" {
+		t.Errorf("GetWorkspaces = %+v, want the server's answer", workspaces)
+	}
+
+	thread, err := client.CreateThread(7, "Plans", "hello", nil)
+	if err != nil {
+		t.Fatalf("CreateThread: %v", err)
+	}
+	if thread.ID != 0 {
+		t.Errorf("dry-run thread ID = %d, want 0", thread.ID)
+	}
+
+	var progress int64
+	if _, err := client.UploadAttachmentFrom("thread", 5, "notes.txt", strings.NewReader("file contents"), func(n int64) {
+		progress += n
+	}); err != nil {
+		t.Fatalf("UploadAttachmentFrom: %v", err)
+	}
+	if progress != int64(len("file contents")) {
+		t.Errorf("upload read %d bytes, want the whole file", progress)
+	}
+
+	if len(sent) != 1 || sent[0] != "GET /api/v3/workspaces/get" {
+		t.Errorf("server received %q, want only the GET", sent)
+	}
+
+	printed := out.String()
+	for _, want := range []string{
+		"[dry-run] POST " + BaseURL + "/threads/add\n",
+		"{\n  \"channel_id\": 7,\n  \"content\": \"hello\",\n  \"title\": \"Plans\"\n}\n",
+		"[dry-run] POST " + BaseURL + "/attachments/upload\n",
+		"[dry-run] <multipart/form-data; boundary=",
+	} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("dry-run output is missing %q:\n%s", want, printed)
+		}
+	}
+	if strings.Contains(printed, "file contents") {
+		t.Errorf("dry-run output shows the uploaded file:\n%s", printed)
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import "fmt"
+
+const WebURL = "https://twist.com"
+
+func ThreadURL(workspaceID, channelID, threadID int) string {
+	return fmt.Sprintf("%s/a/%d/ch/%d/t/%d/", WebURL, workspaceID, channelID, threadID)
+}
+
+func CommentURL(workspaceID, channelID, threadID, commentID int) string {
+	return fmt.Sprintf("%s/a/%d/ch/%d/t/%d/c/%d", WebURL, workspaceID, channelID, threadID, commentID)
+}
+
+func MessageURL(workspaceID, conversationID, messageID int) string {
+	return fmt.Sprintf("%s/a/%d/msg/%d/m/%d", WebURL, workspaceID, conversationID, messageID)
+}
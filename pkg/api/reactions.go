@@ -15,8 +15,8 @@ type Reaction struct {
 }
 
 func (c *Client) AddReaction(objectType string, objectID int, emoji string) (*Reaction, error) {
-	if objectType != "thread" && objectType != "comment" {
-		return nil, fmt.Errorf("invalid object type: must be 'thread' or 'comment'")
+	if objectType != "thread" && objectType != "comment" && objectType != "message" {
+		return nil, fmt.Errorf("invalid object type: must be 'thread', 'comment' or 'message'")
 	}
 
 	payload := map[string]interface{}{
@@ -58,8 +58,8 @@ func (c *Client) AddReaction(objectType string, objectID int, emoji string) (*Re
 }
 
 func (c *Client) RemoveReaction(objectType string, objectID int, emoji string) error {
-	if objectType != "thread" && objectType != "comment" {
-		return fmt.Errorf("invalid object type: must be 'thread' or 'comment'")
+	if objectType != "thread" && objectType != "comment" && objectType != "message" {
+		return fmt.Errorf("invalid object type: must be 'thread', 'comment' or 'message'")
 	}
 
 	payload := map[string]interface{}{
@@ -101,8 +101,10 @@ func (c *Client) GetReactions(objectType string, objectID int) ([]Reaction, erro
 		endpoint = fmt.Sprintf("/reactions/get?thread=%d", objectID)
 	} else if objectType == "comment" {
 		endpoint = fmt.Sprintf("/reactions/get?comment=%d", objectID)
+	} else if objectType == "message" {
+		endpoint = fmt.Sprintf("/reactions/get?message=%d", objectID)
 	} else {
-		return nil, fmt.Errorf("invalid object type: must be 'thread' or 'comment'")
+		return nil, fmt.Errorf("invalid object type: must be 'thread', 'comment' or 'message'")
 	}
 
 	body, err := c.doRequest("GET", endpoint)
@@ -4,17 +4,47 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
-func (c *Client) SearchThreads(workspaceID int, query string, opts map[string]interface{}) ([]Thread, error) {
-	endpoint := fmt.Sprintf("/search?workspace_id=%d&query=%s", workspaceID, url.QueryEscape(query))
-
+// searchParams encodes the search filters shared by all search endpoints.
+// Supported keys: channel_id (int), author_ids ([]int), date_from and
+// date_to (int64 unix time), has_attachments (bool), limit (int) and
+// offset (int).
+func searchParams(opts map[string]interface{}) string {
+	params := ""
 	if channelID, ok := opts["channel_id"].(int); ok {
-		endpoint += fmt.Sprintf("&channel_id=%d", channelID)
+		params += fmt.Sprintf("&channel_id=%d", channelID)
+	}
+	if authorIDs, ok := opts["author_ids"].([]int); ok && len(authorIDs) > 0 {
+		ids := make([]string, len(authorIDs))
+		for i, id := range authorIDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		params += "&author_ids=" + url.QueryEscape(strings.Join(ids, ","))
+	}
+	if from, ok := opts["date_from"].(int64); ok {
+		params += fmt.Sprintf("&date_from=%d", from)
+	}
+	if to, ok := opts["date_to"].(int64); ok {
+		params += fmt.Sprintf("&date_to=%d", to)
+	}
+	if hasAttachments, ok := opts["has_attachments"].(bool); ok && hasAttachments {
+		params += "&has_attachments=true"
 	}
 	if limit, ok := opts["limit"].(int); ok {
-		endpoint += fmt.Sprintf("&limit=%d", limit)
+		params += fmt.Sprintf("&limit=%d", limit)
 	}
+	if offset, ok := opts["offset"].(int); ok && offset > 0 {
+		params += fmt.Sprintf("&offset=%d", offset)
+	}
+	return params
+}
+
+func (c *Client) SearchThreads(workspaceID int, query string, opts map[string]interface{}) ([]Thread, error) {
+	endpoint := fmt.Sprintf("/search?workspace_id=%d&query=%s", workspaceID, url.QueryEscape(query))
+	endpoint += searchParams(opts)
 
 	body, err := c.doRequest("GET", endpoint)
 	if err != nil {
@@ -31,10 +61,7 @@ func (c *Client) SearchThreads(workspaceID int, query string, opts map[string]in
 
 func (c *Client) SearchMessages(workspaceID int, query string, opts map[string]interface{}) ([]Comment, error) {
 	endpoint := fmt.Sprintf("/search/comments?workspace_id=%d&query=%s", workspaceID, url.QueryEscape(query))
-
-	if limit, ok := opts["limit"].(int); ok {
-		endpoint += fmt.Sprintf("&limit=%d", limit)
-	}
+	endpoint += searchParams(opts)
 
 	body, err := c.doRequest("GET", endpoint)
 	if err != nil {
@@ -51,10 +78,7 @@ func (c *Client) SearchMessages(workspaceID int, query string, opts map[string]i
 
 func (c *Client) SearchConversations(query string, opts map[string]interface{}) ([]ConversationMessage, error) {
 	endpoint := fmt.Sprintf("/search/messages?query=%s", url.QueryEscape(query))
-
-	if limit, ok := opts["limit"].(int); ok {
-		endpoint += fmt.Sprintf("&limit=%d", limit)
-	}
+	endpoint += searchParams(opts)
 
 	body, err := c.doRequest("GET", endpoint)
 	if err != nil {
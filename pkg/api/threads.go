@@ -27,6 +27,8 @@ type Comment struct {
 	ID            int    `json:"id"`
 	Content       string `json:"content"`
 	ThreadID      int    `json:"thread_id"`
+	ChannelID     int    `json:"channel_id"`
+	WorkspaceID   int    `json:"workspace_id"`
 	Creator       int    `json:"creator"`
 	PostedTS      int64  `json:"posted_ts"`
 	LastUpdatedTS int64  `json:"last_updated_ts"`
@@ -47,6 +49,34 @@ func (c *Client) GetThreads(channelID int) ([]Thread, error) {
 	return threads, nil
 }
 
+// GetThreadsPage fetches one page of a channel's threads, most recently
+// updated first. Supported options: older_than_ts and newer_than_ts (int64,
+// compared with last_updated_ts) and limit (int).
+func (c *Client) GetThreadsPage(channelID int, opts map[string]interface{}) ([]Thread, error) {
+	endpoint := fmt.Sprintf("/threads/get?channel_id=%d", channelID)
+	if ts, ok := opts["older_than_ts"].(int64); ok {
+		endpoint += fmt.Sprintf("&older_than_ts=%d", ts)
+	}
+	if ts, ok := opts["newer_than_ts"].(int64); ok {
+		endpoint += fmt.Sprintf("&newer_than_ts=%d", ts)
+	}
+	if limit, ok := opts["limit"].(int); ok {
+		endpoint += fmt.Sprintf("&limit=%d", limit)
+	}
+
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []Thread
+	if err := json.Unmarshal(body, &threads); err != nil {
+		return nil, fmt.Errorf("failed to parse threads response: %w", err)
+	}
+
+	return threads, nil
+}
+
 func (c *Client) GetThread(id int) (*Thread, error) {
 	endpoint := fmt.Sprintf("/threads/getone?id=%d", id)
 	body, err := c.doRequest("GET", endpoint)
@@ -77,7 +107,22 @@ func (c *Client) GetComments(threadID int) ([]Comment, error) {
 	return comments, nil
 }
 
-func (c *Client) CreateThread(channelID int, title, content string, recipients []int) (*Thread, error) {
+func (c *Client) GetComment(id int) (*Comment, error) {
+	endpoint := fmt.Sprintf("/comments/getone?id=%d", id)
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment Comment
+	if err := json.Unmarshal(body, &comment); err != nil {
+		return nil, fmt.Errorf("failed to parse comment response: %w", err)
+	}
+
+	return &comment, nil
+}
+
+func (c *Client) CreateThread(channelID int, title, content string, recipients []int, attachments ...Attachment) (*Thread, error) {
 	payload := map[string]interface{}{
 		"channel_id": channelID,
 		"title":      title,
@@ -88,6 +133,10 @@ func (c *Client) CreateThread(channelID int, title, content string, recipients [
 		payload["recipients"] = recipients
 	}
 
+	if len(attachments) > 0 {
+		payload["attachments"] = attachments
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal thread data: %w", err)
@@ -120,7 +169,7 @@ func (c *Client) CreateThread(channelID int, title, content string, recipients [
 	return &thread, nil
 }
 
-func (c *Client) PostComment(threadID int, content string, recipients []int) (*Comment, error) {
+func (c *Client) PostComment(threadID int, content string, recipients []int, attachments ...Attachment) (*Comment, error) {
 	payload := map[string]interface{}{
 		"thread_id": threadID,
 		"content":   content,
@@ -130,6 +179,10 @@ func (c *Client) PostComment(threadID int, content string, recipients []int) (*C
 		payload["recipients"] = recipients
 	}
 
+	if len(attachments) > 0 {
+		payload["attachments"] = attachments
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal comment data: %w", err)
@@ -427,6 +480,230 @@ func (c *Client) UnarchiveThread(id int) error {
 	return nil
 }
 
+func (c *Client) FollowThread(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/follow"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) UnfollowThread(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/unfollow"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) MuteThread(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/mute"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) UnmuteThread(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/unmute"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) MarkThreadRead(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/mark_read"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) MarkThreadUnread(id int) error {
+	payload := map[string]interface{}{
+		"id": id,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/mark_unread"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) MarkAllThreadsRead(channelID int) error {
+	payload := map[string]interface{}{
+		"channel_id": channelID,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	url := BaseURL + "/threads/mark_all_read"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (c *Client) UpdateComment(id int, content string) (*Comment, error) {
 	payload := map[string]interface{}{
 		"id":      id,
@@ -28,3 +28,18 @@ func (c *Client) GetWorkspaceUsers(workspaceID int) ([]User, error) {
 
 	return users, nil
 }
+
+// GetSessionUser returns the user the token belongs to.
+func (c *Client) GetSessionUser() (*User, error) {
+	body, err := c.doRequest("GET", "/users/get_session_user")
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	}
+
+	return &user, nil
+}